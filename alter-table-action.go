@@ -0,0 +1,76 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"fmt"
+)
+
+// AlterTableActionType represents the kind of an AlterTableAction.
+type AlterTableActionType int
+
+const (
+	AlterTableAddColumn AlterTableActionType = iota
+	AlterTableChangeColumn
+	AlterTableDropColumn
+	AlterTableAddForeignKey
+	AlterTableDropForeignKey
+	AlterTableRename
+)
+
+func (t AlterTableActionType) String() string {
+	switch t {
+	case AlterTableAddColumn:
+		return "add column"
+	case AlterTableChangeColumn:
+		return "change column"
+	case AlterTableDropColumn:
+		return "drop column"
+	case AlterTableAddForeignKey:
+		return "add foreign key"
+	case AlterTableDropForeignKey:
+		return "drop foreign key"
+	case AlterTableRename:
+		return "rename"
+	}
+	return fmt.Sprintf("AlterTableActionType(%d)", int(t))
+}
+
+// AlterTableAction represents one change of an ALTER TABLE statement, given
+// to the Dialect for rendering.
+type AlterTableAction struct {
+	Type AlterTableActionType
+
+	// Column is the column added, or the new definition of a changed column
+	Column ColumnConfig
+	// OldColumn is the current definition of a changed or dropped column
+	OldColumn ColumnConfig
+	// First and After position an added or changed column, when supported
+	First bool
+	After string
+
+	// ForeignKey is the foreign key added, or dropped by name
+	ForeignKey *ForeignKey
+
+	// Name is the new name of the table
+	Name string
+}
@@ -30,49 +30,27 @@ type cAlterTableAddColumn struct {
 	dialect Dialect
 }
 
-func (c *cAlterTableAddColumn) serialize(b *builder) {
-	b.Append("ADD COLUMN ")
-	b.AppendItem(c.column)
-
-	// SQL data name
-	typ, err := c.dialect.ColumnTypeToString(c.column)
-	if err != nil {
-		b.SetError(err)
-	} else if len(typ) == 0 {
-		b.SetError(newError("column type is required.(maybe, a bug is in implements of dialect.)"))
-	} else {
-		b.Append(" ")
-		b.Append(typ)
-	}
-
-	opt, err := c.dialect.ColumnOptionToString(c.column.Option())
+func (c *cAlterTableAddColumn) action() (AlterTableAction, error) {
+	after, err := alterTableColumnName(c.after)
 	if err != nil {
-		b.SetError(err)
-	} else if len(opt) != 0 {
-		b.Append(" ")
-		b.Append(opt)
-	}
-
-	if c.first {
-		b.Append(" FIRST")
-	} else if c.after != nil {
-		b.Append(" AFTER ")
-		if name := c.after.column_name(); len(name) != 0 {
-			b.Append(c.dialect.QuoteField(name))
-		} else {
-			b.AppendItem(c.after)
-		}
+		return AlterTableAction{}, err
 	}
+	return AlterTableAction{
+		Type:   AlterTableAddColumn,
+		Column: c.column,
+		First:  c.first,
+		After:  after,
+	}, nil
 }
 
-func (c *cAlterTableAddColumn) applyToTable() error {
+func (c *cAlterTableAddColumn) applyTo(t *cTable) error {
 	if c.first {
-		return c.table.AddColumnFirst(c.column)
+		return t.AddColumnFirst(c.column)
 	}
 	if c.after != nil {
-		return c.table.AddColumnAfter(c.column, c.after)
+		return t.AddColumnAfter(c.column, t.C(c.after.column_name()))
 	}
-	return c.table.AddColumnLast(c.column)
+	return t.AddColumnLast(c.column)
 }
 
 func (c *cAlterTableAddColumn) Describe() (output string) {
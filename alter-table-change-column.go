@@ -31,54 +31,32 @@ type cAlterTableChangeColumn struct {
 	dialect Dialect
 }
 
-func (c *cAlterTableChangeColumn) serialize(b *builder) {
-	b.Append("CHANGE COLUMN ")
-	if name := c.old_column.column_name(); len(name) != 0 {
-		b.Append(c.dialect.QuoteField(name))
-	} else {
-		b.AppendItem(c.old_column)
+func (c *cAlterTableChangeColumn) action() (AlterTableAction, error) {
+	if err := GetColumnError(c.old_column); err != nil {
+		return AlterTableAction{}, err
 	}
-	b.Append(" ")
-	b.AppendItem(c.new_column)
-
-	typ, err := c.dialect.ColumnTypeToString(c.new_column)
-	if err != nil {
-		b.SetError(err)
-	} else if len(typ) == 0 {
-		b.SetError(newError("column type is required.(maybe, a bug is in implements of dialect.)"))
-	} else {
-		b.Append(" ")
-		b.Append(typ)
-	}
-
-	opt, err := c.dialect.ColumnOptionToString(c.new_column.Option())
+	after, err := alterTableColumnName(c.after)
 	if err != nil {
-		b.SetError(err)
-	} else if len(opt) != 0 {
-		b.Append(" ")
-		b.Append(opt)
-	}
-
-	if c.first {
-		b.Append(" FIRST")
-	} else if c.after != nil {
-		b.Append(" AFTER ")
-		if colname := c.after.column_name(); len(colname) != 0 {
-			b.Append(c.dialect.QuoteField(colname))
-		} else {
-			b.AppendItem(c.after)
-		}
+		return AlterTableAction{}, err
 	}
+	return AlterTableAction{
+		Type:      AlterTableChangeColumn,
+		Column:    c.new_column,
+		OldColumn: c.old_column.config(),
+		First:     c.first,
+		After:     after,
+	}, nil
 }
 
-func (c *cAlterTableChangeColumn) applyToTable() error {
+func (c *cAlterTableChangeColumn) applyTo(t *cTable) error {
+	old_column := t.C(c.old_column.column_name())
 	if c.first {
-		return c.table.ChangeColumnFirst(c.old_column, c.new_column)
+		return t.ChangeColumnFirst(old_column, c.new_column)
 	}
 	if c.after != nil {
-		return c.table.ChangeColumnAfter(c.old_column, c.new_column, c.after)
+		return t.ChangeColumnAfter(old_column, c.new_column, t.C(c.after.column_name()))
 	}
-	return c.table.ChangeColumn(c.old_column, c.new_column)
+	return t.ChangeColumn(old_column, c.new_column)
 }
 
 func (c *cAlterTableChangeColumn) Describe() (output string) {
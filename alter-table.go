@@ -31,9 +31,22 @@ type AlterTableBuilder interface {
 	ChangeColumn(old_column Column, new_column ColumnConfig) AlterTableBuilder
 	ChangeColumnAfter(old_column Column, new_column ColumnConfig, after Column) AlterTableBuilder
 	ChangeColumnFirst(old_column Column, new_column ColumnConfig) AlterTableBuilder
+	AddForeignKey(fk ForeignKey) AlterTableBuilder
+	DropForeignKey(name string) AlterTableBuilder
 	ToSql() (query string, args []interface{}, err error)
 	ApplyToTable() error
 
+	// Statements returns the statements applying the changes, one for each
+	// statement of the dialect. ToSql fails when the dialect needs more than
+	// one statement, such as SQLite with several changes, as drivers may not
+	// run them in a single query. Executor runs them one at a time.
+	Statements() ([]Statement, error)
+
+	// Rebuild returns the statements recreating the table with the changes
+	// applied, for dialects such as SQLite which can not alter most of a
+	// table in place. The indexes given are created last.
+	Rebuild(indexes ...CreateIndexBuilder) (queries []string, err error)
+
 	privateAlterTable()
 }
 
@@ -71,6 +84,8 @@ type cAlterTable struct {
 	add_columns    []*cAlterTableAddColumn
 	drop_columns   []Column
 	change_columns []*cAlterTableChangeColumn
+	add_fkeys      []ForeignKey
+	drop_fkeys     []string
 
 	err     error
 	dialect Dialect
@@ -191,79 +206,293 @@ func (b *cAlterTable) ChangeColumnFirst(old_column Column, new_column ColumnConf
 	return b
 }
 
+func (b *cAlterTable) AddForeignKey(fk ForeignKey) AlterTableBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	if err := fk.check(b.table); err != nil {
+		b.err = err
+		return b
+	}
+	b.add_fkeys = append(b.add_fkeys, fk)
+	return b
+}
+
+func (b *cAlterTable) DropForeignKey(name string) AlterTableBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	if len(name) == 0 {
+		b.err = newError("foreign key name is required.")
+		return b
+	}
+	b.drop_fkeys = append(b.drop_fkeys, name)
+	return b
+}
+
 func (b *cAlterTable) ToSql() (query string, args []interface{}, err error) {
-	bldr := newBuilder(b.dialect)
-	defer func() {
-		query, args, err = bldr.Query(), bldr.Args(), bldr.Err()
-	}()
+	statements, err := b.Statements()
+	if err != nil {
+		return "", []interface{}{}, err
+	} else if len(statements) > 1 {
+		return "", []interface{}{}, newError("AlterTable needs %d statements in the %s dialect. (see AlterTableBuilder.Statements)",
+			len(statements), b.dialect.Name())
+	}
+	return statements[0].ToSql()
+}
+
+func (b *cAlterTable) Statements() ([]Statement, error) {
 	if b.err != nil {
-		bldr.SetError(b.err)
-		return
+		return nil, b.err
 	}
 
-	bldr.Append("ALTER TABLE ")
-	bldr.AppendItem(b.table)
-	bldr.Append(" ")
+	actions, err := b.actions()
+	if err != nil {
+		return nil, err
+	} else if len(actions) == 0 {
+		return nil, newError("AlterTable needs one or more changes.")
+	}
+
+	queries, err := b.dialect.AlterTableToString(b.table.Name(), actions)
+	if err != nil {
+		return nil, err
+	} else if len(queries) == 0 {
+		return nil, newError("AlterTable needs one or more changes.")
+	}
+	statements := make([]Statement, len(queries))
+	for idx, query := range queries {
+		statements[idx] = cAlterTableStatement(query + b.dialect.QuerySuffix())
+	}
+	return statements, nil
+}
+
+// cAlterTableStatement is one of the statements of an AlterTable, as rendered
+// by the dialect
+type cAlterTableStatement string
 
-	first := true
+func (s cAlterTableStatement) ToSql() (query string, args []interface{}, err error) {
+	return string(s), []interface{}{}, nil
+}
+
+// actions returns the changes of this statement in the order given to the
+// Dialect
+func (b *cAlterTable) actions() (actions []AlterTableAction, err error) {
 	for _, add_column := range b.add_columns {
-		if !first {
-			bldr.Append(", ")
+		action, err := add_column.action()
+		if err != nil {
+			return nil, err
 		}
-		first = false
-		bldr.AppendItem(add_column)
+		actions = append(actions, action)
 	}
 	for _, change_column := range b.change_columns {
-		if !first {
-			bldr.Append(", ")
+		action, err := change_column.action()
+		if err != nil {
+			return nil, err
 		}
-		first = false
-		bldr.AppendItem(change_column)
+		actions = append(actions, action)
 	}
 	for _, drop_column := range b.drop_columns {
-		if !first {
-			bldr.Append(", ")
-		}
-		first = false
-		bldr.Append("DROP COLUMN ")
-		if colname := drop_column.column_name(); len(colname) != 0 {
-			bldr.Append(b.dialect.QuoteField(colname))
-		} else {
-			bldr.AppendItem(drop_column)
+		if err = GetColumnError(drop_column); err != nil {
+			return nil, err
 		}
+		actions = append(actions, AlterTableAction{
+			Type:      AlterTableDropColumn,
+			OldColumn: drop_column.config(),
+		})
+	}
+	for idx := range b.add_fkeys {
+		actions = append(actions, AlterTableAction{
+			Type:       AlterTableAddForeignKey,
+			ForeignKey: &b.add_fkeys[idx],
+		})
+	}
+	for _, name := range b.drop_fkeys {
+		actions = append(actions, AlterTableAction{
+			Type:       AlterTableDropForeignKey,
+			ForeignKey: &ForeignKey{Name: name},
+		})
 	}
 	if len(b.rename_to) != 0 {
-		if !first {
-			bldr.Append(", ")
-		}
-		bldr.Append("RENAME TO ")
-		bldr.Append(b.dialect.QuoteField(b.rename_to))
+		actions = append(actions, AlterTableAction{
+			Type: AlterTableRename,
+			Name: b.rename_to,
+		})
 	}
-
-	return "", nil, nil
+	return
 }
 
 func (b *cAlterTable) ApplyToTable() error {
+	if b.err != nil {
+		return b.err
+	}
+	return b.applyTo(b.table)
+}
+
+func (b *cAlterTable) applyTo(t *cTable) error {
 	for _, add_column := range b.add_columns {
-		err := add_column.applyToTable()
+		err := add_column.applyTo(t)
 		if err != nil {
 			return err
 		}
 	}
 	for _, change_column := range b.change_columns {
-		err := change_column.applyToTable()
+		err := change_column.applyTo(t)
 		if err != nil {
 			return err
 		}
 	}
 	for _, drop_column := range b.drop_columns {
-		err := b.table.DropColumn(drop_column)
+		err := t.DropColumn(t.C(drop_column.column_name()))
+		if err != nil {
+			return err
+		}
+	}
+	for _, fk := range b.add_fkeys {
+		err := t.AddForeignKey(fk)
+		if err != nil {
+			return err
+		}
+	}
+	for _, name := range b.drop_fkeys {
+		err := t.DropForeignKey(name)
 		if err != nil {
 			return err
 		}
 	}
 	if len(b.rename_to) != 0 {
-		b.table.SetName(b.rename_to)
+		t.SetName(b.rename_to)
 	}
 	return nil
 }
+
+// Rebuild returns the table rebuild sequence: CREATE TABLE of the changed
+// table under a temporary name, INSERT ... SELECT of the rows kept, DROP
+// TABLE of the original, RENAME TO of the new table, then CREATE INDEX of the
+// indexes given. The Table is not changed, use ApplyToTable once the
+// statements are executed.
+//
+// With SQLite, the statements are expected to run within a transaction while
+// "PRAGMA foreign_keys" is off, followed by "PRAGMA foreign_key_check".
+func (b *cAlterTable) Rebuild(indexes ...CreateIndexBuilder) (queries []string, err error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if _, err = b.actions(); err != nil {
+		return nil, err
+	}
+
+	next := b.table.clone()
+	if err = b.applyTo(next); err != nil {
+		return nil, err
+	}
+	if err = next.checkOption(); err != nil {
+		return nil, err
+	}
+	name := next.name
+	next.name = "new_" + name
+
+	// the kept columns of the new table, by the original column
+	from := make(map[string]Column, len(b.table.columns))
+	for _, col := range b.table.columns {
+		from[col.column_name()] = col
+	}
+	for _, add_column := range b.add_columns {
+		delete(from, add_column.column.Name())
+	}
+	for _, change_column := range b.change_columns {
+		if col, ok := from[change_column.old_column.column_name()]; ok {
+			delete(from, col.column_name())
+			from[change_column.new_column.Name()] = col
+		}
+	}
+	var columns, sources []Column
+	renamed := make(map[string]string, len(from))
+	for _, col := range next.columns {
+		if src, ok := from[col.column_name()]; ok {
+			columns = append(columns, col)
+			sources = append(sources, src)
+			renamed[src.column_name()] = col.column_name()
+		}
+	}
+
+	// the indexes are created on the renamed table, with renamed columns
+	final := next.clone()
+	final.name = name
+	creates := make([]Statement, 0, len(indexes))
+	for _, index := range indexes {
+		create, err := b.rebuildIndex(index, final, renamed)
+		if err != nil {
+			return nil, err
+		}
+		creates = append(creates, create)
+	}
+
+	statements := []Statement{createTable(next, b.dialect)}
+	if len(columns) != 0 {
+		statements = append(statements, insert(next, b.dialect).
+			Columns(columns...).
+			FromSelect(selectFn(b.table, b.dialect).Columns(sources...)))
+	}
+	statements = append(statements,
+		dropTable(b.table, b.dialect),
+		alterTable(next, b.dialect).RenameTo(name),
+	)
+	statements = append(statements, creates...)
+
+	queries = make([]string, 0, len(statements))
+	for _, stmt := range statements {
+		query, _, err := stmt.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, query)
+	}
+	return queries, nil
+}
+
+// alterTableColumnName returns the name of the column given, which may be nil
+func alterTableColumnName(col Column) (string, error) {
+	if col == nil {
+		return "", nil
+	}
+	if err := GetColumnError(col); err != nil {
+		return "", err
+	}
+	return col.column_name(), nil
+}
+
+// rebuildIndex returns the index given, created on the rebuilt table. The
+// columns of an index of the original table are renamed, or rejected when
+// dropped.
+func (b *cAlterTable) rebuildIndex(index CreateIndexBuilder, t *cTable, renamed map[string]string) (Statement, error) {
+	create, ok := index.(*cCreateIndex)
+	if !ok || create == nil {
+		return nil, newError("got %T type, but index is not a CREATE INDEX statement.", index)
+	}
+	if create.err != nil {
+		return nil, create.err
+	}
+	original := create.table == Table(b.table)
+	if !original && create.table.Name() != b.table.name && create.table.Name() != t.name {
+		return nil, newError("index %s is not an index of table %s.", create.name, b.table.name)
+	}
+
+	rebuilt := *create
+	rebuilt.table = t
+	rebuilt.columns = make([]Column, 0, len(create.columns))
+	for _, col := range create.columns {
+		name := col.column_name()
+		if original {
+			if name, ok = renamed[name]; !ok {
+				return nil, newError("index %s uses column %s.%s, which is dropped.", create.name, b.table.name, col.column_name())
+			}
+		}
+		if !t.hasColumnName(name) {
+			return nil, newError("column %s.%s was not found.", t.name, name)
+		}
+		rebuilt.columns = append(rebuilt.columns, t.C(name))
+	}
+	return &rebuilt, nil
+}
@@ -22,6 +22,7 @@
 package sqlbuilder
 
 import (
+	"database/sql"
 	"testing"
 )
 
@@ -98,6 +99,34 @@ func TestAlterTable(t *testing.T) {
 		query:  `ALTER TABLE "TABLE_A" DROP COLUMN "test1";`,
 		args:   []interface{}{},
 		errmsg: "",
+	}, {
+		stmt: AlterTable(table1).
+			AddForeignKey(ForeignKey{
+				Name:       "FK_TEST2",
+				Columns:    []string{"test2"},
+				RefTable:   "TABLE_B",
+				RefColumns: []string{"id"},
+				OnDelete:   ForeignKeyCascade,
+			}).
+			DropForeignKey("FK_TEST1"),
+		query:  `ALTER TABLE "TABLE_A" ADD CONSTRAINT "FK_TEST2" FOREIGN KEY ("test2") REFERENCES "TABLE_B" ("id") ON DELETE CASCADE, DROP CONSTRAINT "FK_TEST1";`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt:   AlterTable(table1).AddForeignKey(ForeignKey{Columns: []string{"invalid"}, RefTable: "TABLE_B"}),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: column TABLE_A.invalid was not found.",
+	}, {
+		stmt:   AlterTable(table1).AddForeignKey(ForeignKey{Columns: []string{"test1", "test2"}, RefTable: "TABLE_B", RefColumns: []string{"id"}}),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: foreign key has 2 columns, but references 1.",
+	}, {
+		stmt:   AlterTable(table1).DropForeignKey(""),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: foreign key name is required.",
 	}, {
 		stmt:   AlterTable(table1).DropColumn(table1.C("invalid")),
 		query:  ``,
@@ -118,6 +147,11 @@ func TestAlterTable(t *testing.T) {
 		query:  ``,
 		args:   []interface{}{},
 		errmsg: "sqlbuilder: column TABLE_A.invalid was not found.",
+	}, {
+		stmt:   AlterTable(table1),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: AlterTable needs one or more changes.",
 	}, {
 		stmt:   AlterTable(nil).DropColumn(table1.C("invalid")),
 		query:  ``,
@@ -143,6 +177,7 @@ func TestAlterTableApplyToTable(t *testing.T) {
 		stmt           func(Table) AlterTableBuilder
 		expect_columns []string
 		expect_name    string
+		expect_fkeys   []string
 	}{{
 		stmt: func(t Table) AlterTableBuilder {
 			return AlterTable(t).
@@ -163,12 +198,23 @@ func TestAlterTableApplyToTable(t *testing.T) {
 		},
 		expect_columns: []string{"id", "test2", "test1a"},
 		expect_name:    "TABLE_A",
+	}, {
+		stmt: func(t Table) AlterTableBuilder {
+			return AlterTable(t).
+				AddForeignKey(ForeignKey{Name: "FK_TEST2", Columns: []string{"test2"}, RefTable: "TABLE_B"}).
+				DropForeignKey("FK_TEST1")
+		},
+		expect_columns: []string{"id", "test1", "test2"},
+		expect_name:    "TABLE_A",
+		expect_fkeys:   []string{"FK_TEST2"},
 	}}
 
 	for num, c := range cases {
 		table1 := NewTable(
 			"TABLE_A",
-			&TableOption{},
+			&TableOption{
+				ForeignKeys: []ForeignKey{{Name: "FK_TEST1", Columns: []string{"test1"}, RefTable: "TABLE_B"}},
+			},
 			IntColumn("id", &ColumnOption{
 				PrimaryKey: true,
 			}),
@@ -192,5 +238,179 @@ func TestAlterTableApplyToTable(t *testing.T) {
 		if table1.Name() != c.expect_name {
 			t.Errorf("failed on %d", num)
 		}
+		if c.expect_fkeys != nil {
+			fkeys := table1.Option().ForeignKeys
+			if len(fkeys) != len(c.expect_fkeys) {
+				t.Errorf("failed on %d", num)
+			}
+			for i := range fkeys {
+				if c.expect_fkeys[i] != fkeys[i].Name {
+					t.Errorf("failed on %d", num)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestAlterTableRebuild(t *testing.T) {
+	table1 := NewTable(
+		"TABLE_A",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		IntColumn("test1", nil),
+		IntColumn("test2", nil),
+	)
+	index := CreateIndex(table1).Name("I_TABLE_A").Columns(table1.C("test2"))
+
+	queries, err := AlterTable(table1).
+		AddColumnFirst(StringColumn("test0", nil)).
+		ChangeColumn(table1.C("test1"), StringColumn("test1a", nil)).
+		DropColumn(table1.C("id")).
+		Rebuild(index)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	expect := []string{
+		`CREATE TABLE "new_TABLE_A" ( "test0" TEXT, "test1a" TEXT, "test2" INTEGER );`,
+		`INSERT INTO "new_TABLE_A" ( "test1a", "test2" ) SELECT "TABLE_A"."test1", "TABLE_A"."test2" FROM "TABLE_A";`,
+		`DROP TABLE "TABLE_A";`,
+		`ALTER TABLE "new_TABLE_A" RENAME TO "TABLE_A";`,
+		`CREATE INDEX "I_TABLE_A" ON "TABLE_A" ( "test2" );`,
+	}
+	if len(queries) != len(expect) {
+		t.Fatalf("failed\nexpect: %#v\ngot: %#v", expect, queries)
+	}
+	for i := range expect {
+		if queries[i] != expect[i] {
+			t.Errorf("failed\nexpect: %s\ngot: %s", expect[i], queries[i])
+		}
+	}
+	if len(table1.Columns()) != 3 || table1.C("test1").column_name() != "test1" {
+		t.Errorf("failed: table was changed by Rebuild")
+	}
+
+	_, err = AlterTable(table1).DropColumn(table1.C("invalid")).Rebuild()
+	if err == nil || err.Error() != "sqlbuilder: column TABLE_A.invalid was not found." {
+		t.Errorf("failed: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	exec := func(query string) {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("failed: %s\n%s", err, query)
+		}
+	}
+	query, _, _ := CreateTable(table1).ToSql()
+	exec(query)
+	exec(`INSERT INTO "TABLE_A" VALUES (1, 10, 20), (2, 11, 21);`)
+	for _, query = range queries {
+		exec(query)
+	}
+	var test1a string
+	var count int
+	if err = db.QueryRow(`SELECT "test1a", COUNT(*) FROM "TABLE_A" WHERE "test2"=21;`).Scan(&test1a, &count); err != nil {
+		t.Fatal(err)
+	}
+	if test1a != "11" || count != 1 {
+		t.Errorf("failed: got %q, %d", test1a, count)
+	}
+}
+
+func TestAlterTableRebuildConstraints(t *testing.T) {
+	table1 := NewTable(
+		"TABLE_A",
+		&TableOption{
+			PrimaryKey: []string{"id"},
+			Unique:     [][]string{{"test1", "test2"}},
+		},
+		IntColumn("id", nil),
+		IntColumn("test1", nil),
+		IntColumn("test2", nil),
+		IntColumn("test3", nil),
+	)
+	table1.Option().Checks = []Check{{Name: "CK_TEST1", Condition: table1.C("test1").Gt(0)}}
+	index := CreateIndex(table1).Name("I_TABLE_A").Columns(table1.C("test1"), table1.C("test3"))
+
+	queries, err := AlterTable(table1).
+		ChangeColumn(table1.C("id"), IntColumn("key", nil)).
+		ChangeColumn(table1.C("test1"), IntColumn("test1a", nil)).
+		RenameTo("TABLE_B").
+		Rebuild(index)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	expect := []string{
+		`CREATE TABLE "new_TABLE_B" ( "key" INTEGER, "test1a" INTEGER, "test2" INTEGER, "test3" INTEGER, PRIMARY KEY ("key"), UNIQUE("test1a", "test2"), CONSTRAINT "CK_TEST1" CHECK ("test1a">0) );`,
+		`INSERT INTO "new_TABLE_B" ( "key", "test1a", "test2", "test3" ) SELECT "TABLE_A"."id", "TABLE_A"."test1", "TABLE_A"."test2", "TABLE_A"."test3" FROM "TABLE_A";`,
+		`DROP TABLE "TABLE_A";`,
+		`ALTER TABLE "new_TABLE_B" RENAME TO "TABLE_B";`,
+		`CREATE INDEX "I_TABLE_A" ON "TABLE_B" ( "test1a", "test3" );`,
+	}
+	if len(queries) != len(expect) {
+		t.Fatalf("failed\nexpect: %#v\ngot: %#v", expect, queries)
+	}
+	for i := range expect {
+		if queries[i] != expect[i] {
+			t.Errorf("failed\nexpect: %s\ngot: %s", expect[i], queries[i])
+		}
+	}
+	if opt := table1.Option(); opt.PrimaryKey[0] != "id" || opt.Unique[0][0] != "test1" {
+		t.Errorf("failed: table option was changed by Rebuild")
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	exec := func(query string) {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("failed: %s\n%s", err, query)
+		}
+	}
+	query, _, _ := CreateTable(table1).ToSql()
+	exec(query)
+	exec(`INSERT INTO "TABLE_A" VALUES (1, 10, 20, 30), (2, 11, 21, 31);`)
+	for _, query = range queries {
+		exec(query)
+	}
+	var key int
+	if err = db.QueryRow(`SELECT "key" FROM "TABLE_B" WHERE "test1a"=11;`).Scan(&key); err != nil {
+		t.Fatal(err)
+	}
+	if key != 2 {
+		t.Errorf("failed: got %d", key)
+	}
+	if _, err = db.Exec(`INSERT INTO "TABLE_B" VALUES (3, 0, 22, 32);`); err == nil {
+		t.Errorf("failed: check constraint was not kept")
+	}
+
+	// the constraints of a dropped column are dropped, but not its indexes
+	queries, err = AlterTable(table1).DropColumn(table1.C("test1")).Rebuild()
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	if expect := `CREATE TABLE "new_TABLE_A" ( "id" INTEGER, "test2" INTEGER, "test3" INTEGER, PRIMARY KEY ("id"), UNIQUE("test2") );`; queries[0] != expect {
+		t.Errorf("failed\nexpect: %s\ngot: %s", expect, queries[0])
+	}
+	_, err = AlterTable(table1).DropColumn(table1.C("test1")).Rebuild(index)
+	if err == nil || err.Error() != "sqlbuilder: index I_TABLE_A uses column TABLE_A.test1, which is dropped." {
+		t.Errorf("failed: %v", err)
+	}
+}
+
+func TestAlterTableActionType(t *testing.T) {
+	if s := AlterTableDropForeignKey.String(); s != "drop foreign key" {
+		t.Errorf("failed: %s", s)
+	}
+	if s := AlterTableActionType(42).String(); s != "AlterTableActionType(42)" {
+		t.Errorf("failed: %s", s)
 	}
 }
@@ -0,0 +1,358 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	sb "github.com/go-corelibs/go-sqlbuilder"
+)
+
+// generate returns the gofmt'd Go source declaring the tables given, in name
+// order, for the package named. The defaults are the column defaults which
+// are not literals by table and column name, written as comments
+func generate(pkg string, tables []sb.Table, defaults map[string]map[string]string) ([]byte, error) {
+	tables = append([]sb.Table(nil), tables...)
+	sort.SliceStable(tables, func(i, j int) bool {
+		return tables[i].Name() < tables[j].Name()
+	})
+
+	var body bytes.Buffer
+	imports := map[string]bool{}
+	names := identifiers{}
+	for _, tbl := range tables {
+		if err := generateTable(&body, imports, names.nextTable(tbl.Name()), tbl, defaults[tbl.Name()]); err != nil {
+			return nil, err
+		}
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by sqlbuilder-gen. DO NOT EDIT.\n\n")
+	src.WriteString("package " + pkg + "\n\nimport (\n")
+	var paths []string
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		src.WriteString("\t" + strconv.Quote(path) + "\n")
+	}
+	src.WriteString("\n\tsb \"github.com/go-corelibs/go-sqlbuilder\"\n)\n")
+	src.Write(body.Bytes())
+	return format.Source(src.Bytes())
+}
+
+// generateTable writes the variable of the table, the type holding its
+// columns and the struct of its rows
+func generateTable(w *bytes.Buffer, imports map[string]bool, name string, tbl sb.Table, defaults map[string]string) error {
+	configs := sb.ColumnConfigs(tbl)
+	if len(configs) == 0 {
+		return fmt.Errorf("table %s has no columns", tbl.Name())
+	}
+	// the Table field holds the table itself
+	fields := identifiers{"Table": true}
+	idents := make([]string, len(configs))
+	for idx, cc := range configs {
+		idents[idx] = fields.next(cc.Name())
+	}
+
+	fmt.Fprintf(w, "\n// %sTable is the %q table and its columns\n", name, tbl.Name())
+	fmt.Fprintf(w, "type %sTable struct {\n\tTable sb.Table\n\n", name)
+	for idx := range configs {
+		fmt.Fprintf(w, "\t%s sb.Column\n", idents[idx])
+	}
+	fmt.Fprintf(w, "}\n\n// %s is the %q table\nvar %s = new%sTable()\n\n", name, tbl.Name(), name, name)
+
+	fmt.Fprintf(w, "func new%sTable() *%sTable {\n\tt := sb.NewTable(\n\t\t%q,\n", name, name, tbl.Name())
+	w.WriteString("\t\t" + tableOptionLiteral(tbl.Option()) + ",\n")
+	for _, cc := range configs {
+		literal, err := columnLiteral(imports, cc)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", tbl.Name(), err)
+		}
+		if def, ok := defaults[cc.Name()]; ok {
+			// the default is left to the database
+			w.WriteString("\t\t// default: " + strings.Join(strings.Fields(def), " ") + "\n")
+		}
+		w.WriteString("\t\t" + literal + ",\n")
+	}
+	fmt.Fprintf(w, "\t)\n\treturn &%sTable{\n\t\tTable: t,\n", name)
+	for idx, cc := range configs {
+		fmt.Fprintf(w, "\t\t%s: t.C(%q),\n", idents[idx], cc.Name())
+	}
+	w.WriteString("\t}\n}\n")
+
+	fmt.Fprintf(w, "\n// %sRow is a row of the %q table\ntype %sRow struct {\n", name, tbl.Name(), name)
+	for idx, cc := range configs {
+		tag, err := fieldTag(cc, tbl.Option())
+		if err != nil {
+			return fmt.Errorf("table %s: %w", tbl.Name(), err)
+		}
+		fmt.Fprintf(w, "\t%s %s `%s:%q`\n", idents[idx], fieldType(imports, cc), sb.ScanTag, tag)
+	}
+	w.WriteString("}\n")
+	return nil
+}
+
+// tableOptionLiteral returns the Go expression of the table option
+func tableOptionLiteral(option *sb.TableOption) string {
+	if option == nil || (len(option.PrimaryKey) == 0 && len(option.Unique) == 0) {
+		return "nil"
+	}
+	var parts []string
+	if len(option.PrimaryKey) != 0 {
+		parts = append(parts, "PrimaryKey: "+stringsLiteral(option.PrimaryKey))
+	}
+	if len(option.Unique) != 0 {
+		var unique []string
+		for _, columns := range option.Unique {
+			unique = append(unique, stringsLiteral(columns)[len("[]string"):])
+		}
+		parts = append(parts, "Unique: [][]string{"+strings.Join(unique, ", ")+"}")
+	}
+	return "&sb.TableOption{" + strings.Join(parts, ", ") + "}"
+}
+
+// columnLiteral returns the Go expression of the column config
+func columnLiteral(imports map[string]bool, cc sb.ColumnConfig) (string, error) {
+	var constructor string
+	switch cc.Type() {
+	case sb.ColumnTypeInt:
+		constructor = "sb.IntColumn"
+	case sb.ColumnTypeString:
+		constructor = "sb.StringColumn"
+	case sb.ColumnTypeDate:
+		constructor = "sb.DateColumn"
+	case sb.ColumnTypeFloat:
+		constructor = "sb.FloatColumn"
+	case sb.ColumnTypeBool:
+		constructor = "sb.BoolColumn"
+	case sb.ColumnTypeBytes:
+		constructor = "sb.BytesColumn"
+	default:
+		constructor = "sb.AnyColumn"
+	}
+
+	opt := cc.Option()
+	var parts []string
+	if opt.PrimaryKey {
+		parts = append(parts, "PrimaryKey: true")
+	}
+	if opt.NotNull {
+		parts = append(parts, "NotNull: true")
+	}
+	if opt.Unique {
+		parts = append(parts, "Unique: true")
+	}
+	if opt.AutoIncrement {
+		parts = append(parts, "AutoIncrement: true")
+	}
+	if opt.Size != 0 {
+		parts = append(parts, "Size: "+strconv.Itoa(opt.Size))
+	}
+	if opt.SqlType != "" {
+		parts = append(parts, "SqlType: "+strconv.Quote(opt.SqlType))
+	}
+	if opt.Default != nil {
+		value, err := valueLiteral(imports, opt.Default)
+		if err != nil {
+			return "", fmt.Errorf("column %s: %w", cc.Name(), err)
+		}
+		parts = append(parts, "Default: "+value)
+	}
+	if len(parts) == 0 {
+		return constructor + "(" + strconv.Quote(cc.Name()) + ", nil)", nil
+	}
+	return constructor + "(" + strconv.Quote(cc.Name()) + ", &sb.ColumnOption{" + strings.Join(parts, ", ") + "})", nil
+}
+
+// valueLiteral returns the Go expression of a column default
+func valueLiteral(imports map[string]bool, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case int64:
+		return "int64(" + strconv.FormatInt(v, 10) + ")", nil
+	case float64:
+		return "float64(" + strconv.FormatFloat(v, 'g', -1, 64) + ")", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case string:
+		return strconv.Quote(v), nil
+	case []byte:
+		return "[]byte(" + strconv.Quote(string(v)) + ")", nil
+	case time.Time:
+		imports["time"] = true
+		return fmt.Sprintf("time.Date(%d, %d, %d, %d, %d, %d, %d, time.UTC)",
+			v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), v.Nanosecond()), nil
+	}
+	return "", fmt.Errorf("default of %T type is not supported", value)
+}
+
+// fieldType returns the Go type of the struct field of the column, using
+// the sql.Null types for the columns which are not NOT NULL
+func fieldType(imports map[string]bool, cc sb.ColumnConfig) string {
+	opt := cc.Option()
+	notNull := opt.NotNull || opt.PrimaryKey
+	switch cc.Type() {
+	case sb.ColumnTypeInt:
+		if notNull {
+			return "int64"
+		}
+		imports["database/sql"] = true
+		return "sql.NullInt64"
+	case sb.ColumnTypeString:
+		if notNull {
+			return "string"
+		}
+		imports["database/sql"] = true
+		return "sql.NullString"
+	case sb.ColumnTypeDate:
+		if notNull {
+			imports["time"] = true
+			return "time.Time"
+		}
+		imports["database/sql"] = true
+		return "sql.NullTime"
+	case sb.ColumnTypeFloat:
+		if notNull {
+			return "float64"
+		}
+		imports["database/sql"] = true
+		return "sql.NullFloat64"
+	case sb.ColumnTypeBool:
+		if notNull {
+			return "bool"
+		}
+		imports["database/sql"] = true
+		return "sql.NullBool"
+	case sb.ColumnTypeBytes:
+		return "[]byte"
+	}
+	return "interface{}"
+}
+
+// fieldTag returns the struct tag value declaring the column the same way
+// for NewTableFromStruct. The struct tag is written within backquotes, which
+// the value can not contain
+func fieldTag(cc sb.ColumnConfig, option *sb.TableOption) (string, error) {
+	opt := cc.Option()
+	tag := []string{cc.Name()}
+	primary := opt.PrimaryKey
+	if option != nil {
+		for _, name := range option.PrimaryKey {
+			primary = primary || name == cc.Name()
+		}
+	}
+	if primary {
+		tag = append(tag, "primary")
+	}
+	if opt.NotNull {
+		tag = append(tag, "notnull")
+	}
+	if opt.Unique {
+		tag = append(tag, "unique")
+	}
+	if opt.AutoIncrement {
+		tag = append(tag, "autoincrement")
+	}
+	if opt.Size != 0 {
+		tag = append(tag, "size="+strconv.Itoa(opt.Size))
+	}
+	if opt.SqlType != "" && !strings.Contains(opt.SqlType, ",") {
+		tag = append(tag, "type="+opt.SqlType)
+	}
+	switch v := opt.Default.(type) {
+	case int64, float64, bool:
+		tag = append(tag, fmt.Sprintf("default=%v", v))
+	case string:
+		if !strings.Contains(v, ",") {
+			tag = append(tag, "default="+v)
+		}
+	}
+	value := strings.Join(tag, ",")
+	if strings.Contains(value, "`") {
+		return "", fmt.Errorf("column %s: backquotes can not be used in a struct tag", cc.Name())
+	}
+	return value, nil
+}
+
+// stringsLiteral returns the Go expression of a []string
+func stringsLiteral(values []string) string {
+	quoted := make([]string, len(values))
+	for idx, value := range values {
+		quoted[idx] = strconv.Quote(value)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
+
+// identifiers are the exported Go identifiers used within a scope
+type identifiers map[string]bool
+
+// next returns the exported Go identifier of the SQL name given, ie:
+// "group_id" is "GroupId", made unique within the scope
+func (ids identifiers) next(name string) string {
+	var ident strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if ident.Len() == 0 && unicode.IsDigit(r) {
+			ident.WriteByte('X')
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		ident.WriteRune(r)
+	}
+	base := ident.String()
+	if base == "" {
+		base = "X"
+	}
+	unique := base
+	for idx := 2; ids[unique]; idx++ {
+		unique = base + strconv.Itoa(idx)
+	}
+	ids[unique] = true
+	return unique
+}
+
+// nextTable returns the identifier of the table named, which is unique
+// together with the names of the types generated for the table
+func (ids identifiers) nextTable(name string) string {
+	for {
+		ident := ids.next(name)
+		if !ids[ident+"Table"] && !ids[ident+"Row"] {
+			ids[ident+"Table"], ids[ident+"Row"] = true, true
+			return ident
+		}
+		name = ident
+	}
+}
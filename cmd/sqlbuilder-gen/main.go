@@ -0,0 +1,153 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Command sqlbuilder-gen generates Go source declaring the tables of a
+// database, or of a script of CREATE TABLE statements, for use with
+// go-sqlbuilder.
+//
+// For each table, the generated source has a variable holding the Table and
+// each of its columns, so that misspelled column names are compile errors,
+// and a struct of its rows tagged for ScanRow and NewTableFromStruct.
+//
+// Usage:
+//
+//	sqlbuilder-gen [-dialect sqlite] -dsn <data source> [-package name] [-o file] [-tables a,b]
+//	sqlbuilder-gen -ddl <script.sql> [-package name] [-o file] [-tables a,b]
+//
+// Scripts are loaded into an in-memory SQLite database. Column types are
+// read with the reverse of the ColumnTypeToString mapping of the dialect.
+// Column defaults which are not literals, such as CURRENT_TIMESTAMP, are left
+// to the database and written as comments.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	sb "github.com/go-corelibs/go-sqlbuilder"
+	"github.com/go-corelibs/go-sqlbuilder/dialects"
+)
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "sqlbuilder-gen:", err)
+		os.Exit(1)
+	}
+}
+
+// drivers are the database/sql driver names of the dialects
+var drivers = map[string]string{
+	"sqlite3":    "sqlite3",
+	"mysql":      "mysql",
+	"postgresql": "postgres",
+}
+
+func run(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("sqlbuilder-gen", flag.ContinueOnError)
+	dialectName := flags.String("dialect", "sqlite", "dialect of the database: sqlite, mysql or postgres")
+	dsn := flags.String("dsn", "", "data source name of the database, the file of sqlite databases")
+	ddl := flags.String("ddl", "", "script of CREATE TABLE statements to read instead of a database")
+	pkg := flags.String("package", "schema", "package name of the generated source")
+	output := flags.String("o", "", "file to write, instead of the standard output")
+	only := flags.String("tables", "", "comma separated names of the tables to generate, instead of all")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	d, ok := dialects.Parse(*dialectName)
+	if !ok || drivers[d.Name()] == "" {
+		return fmt.Errorf("dialect %q is not supported", *dialectName)
+	}
+	driver, source := drivers[d.Name()], *dsn
+	if *ddl != "" {
+		d, driver, source = dialects.Sqlite{}, "sqlite3", ":memory:"
+	} else if source == "" {
+		return errors.New("-dsn or -ddl is required")
+	}
+
+	db, err := sql.Open(driver, source)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	// in-memory sqlite databases exist within one connection
+	db.SetMaxOpenConns(1)
+
+	if *ddl != "" {
+		script, err := os.ReadFile(*ddl)
+		if err != nil {
+			return err
+		}
+		if _, err = db.ExecContext(ctx, string(script)); err != nil {
+			return fmt.Errorf("%s: %w", *ddl, err)
+		}
+	}
+
+	tables, err := introspect(ctx, db, d, *only)
+	if err != nil {
+		return err
+	}
+	defaults := map[string]map[string]string{}
+	for _, tbl := range tables {
+		if defaults[tbl.Name()], err = dialects.UnparsedDefaults(ctx, db, d, tbl.Name()); err != nil {
+			return err
+		}
+	}
+	src, err := generate(*pkg, tables, defaults)
+	if err != nil {
+		return err
+	}
+	if *output != "" {
+		return os.WriteFile(*output, src, 0644)
+	}
+	_, err = stdout.Write(src)
+	return err
+}
+
+// introspect returns the tables of the database, or only those named
+func introspect(ctx context.Context, db *sql.DB, d sb.Dialect, only string) ([]sb.Table, error) {
+	if only == "" {
+		return dialects.Introspect(ctx, db, d)
+	}
+	in, ok := d.(dialects.Introspector)
+	if !ok {
+		return nil, fmt.Errorf("dialect %s does not support introspection", d.Name())
+	}
+	var tables []sb.Table
+	for _, name := range strings.Split(only, ",") {
+		tbl, err := in.IntrospectTable(ctx, db, strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, tbl)
+	}
+	return tables, nil
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"go/format"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testScript = `
+CREATE TABLE "people" (
+	"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+	"name" VARCHAR(64) NOT NULL,
+	"nick" TEXT UNIQUE,
+	"age" INTEGER DEFAULT 18,
+	"created" DATETIME NOT NULL DEFAULT '2024-01-02 03:04:05',
+	"tags" JSON,
+	"table" TEXT
+);
+CREATE TABLE "memberships" (
+	"group_id" INTEGER NOT NULL,
+	"person_id" INTEGER NOT NULL,
+	"admin" BOOLEAN NOT NULL DEFAULT FALSE,
+	"joined" DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY ("group_id", "person_id"),
+	UNIQUE ("person_id", "admin")
+);
+`
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	script := filepath.Join(dir, "schema.sql")
+	if err := os.WriteFile(script, []byte(testScript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("DDL script", t, func() {
+		var out bytes.Buffer
+		So(run(ctx, []string{"-ddl", script, "-package", "models"}, &out), ShouldBeNil)
+		src := out.String()
+
+		formatted, err := format.Source(out.Bytes())
+		So(err, ShouldBeNil)
+		So(string(formatted), ShouldEqual, src)
+
+		So(src, ShouldStartWith, "// Code generated by sqlbuilder-gen. DO NOT EDIT.\n\npackage models\n")
+		So(src, ShouldContainSubstring, `"database/sql"`)
+		So(src, ShouldContainSubstring, `"time"`)
+		So(src, ShouldContainSubstring, "var Memberships = newMembershipsTable()")
+		So(src, ShouldContainSubstring, `&sb.TableOption{PrimaryKey: []string{"group_id", "person_id"}, Unique: [][]string{{"person_id", "admin"}}}`)
+		So(src, ShouldContainSubstring, `sb.BoolColumn("admin", &sb.ColumnOption{NotNull: true, Default: false})`)
+		So(src, ShouldContainSubstring, `sb.IntColumn("id", &sb.ColumnOption{PrimaryKey: true, AutoIncrement: true})`)
+		So(src, ShouldContainSubstring, `sb.StringColumn("name", &sb.ColumnOption{NotNull: true, Size: 64, SqlType: "VARCHAR(64)"})`)
+		So(src, ShouldContainSubstring, `sb.IntColumn("age", &sb.ColumnOption{Default: int64(18)})`)
+		So(src, ShouldContainSubstring, `Default: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)`)
+		So(src, ShouldContainSubstring, `sb.AnyColumn("tags", &sb.ColumnOption{SqlType: "JSON"})`)
+		So(src, ShouldContainSubstring, "\t\t// default: CURRENT_TIMESTAMP\n\t\tsb.DateColumn(\"joined\", nil),\n")
+		So(src, ShouldContainSubstring, `Table2:  t.C("table"),`)
+		So(src, ShouldContainSubstring, "Nick    sql.NullString `sql:\"nick,unique\"`")
+		So(src, ShouldContainSubstring, "GroupId  int64        `sql:\"group_id,primary,notnull\"`")
+
+		// tables are generated in name order, whichever order is given
+		var again bytes.Buffer
+		So(run(ctx, []string{"-ddl", script, "-package", "models", "-tables", "people, memberships"}, &again), ShouldBeNil)
+		So(again.String(), ShouldEqual, src)
+	})
+
+	Convey("Output file", t, func() {
+		output := filepath.Join(dir, "schema.go")
+		var out bytes.Buffer
+		So(run(ctx, []string{"-ddl", script, "-o", output, "-tables", "people"}, &out), ShouldBeNil)
+		So(out.Len(), ShouldEqual, 0)
+		written, err := os.ReadFile(output)
+		So(err, ShouldBeNil)
+		So(string(written), ShouldContainSubstring, "package schema\n")
+		So(string(written), ShouldNotContainSubstring, "Memberships")
+	})
+
+	Convey("Errors", t, func() {
+		var out bytes.Buffer
+		So(run(ctx, nil, &out), ShouldNotBeNil)
+		So(run(ctx, []string{"-dialect", "nope", "-dsn", "x"}, &out), ShouldNotBeNil)
+		So(run(ctx, []string{"-ddl", filepath.Join(dir, "missing.sql")}, &out), ShouldNotBeNil)
+		So(run(ctx, []string{"-ddl", script, "-tables", "missing"}, &out), ShouldNotBeNil)
+
+		// struct tags can not hold backquotes
+		quoted := filepath.Join(dir, "quoted.sql")
+		So(os.WriteFile(quoted, []byte("CREATE TABLE \"odd\" ( \"a`b\" INTEGER );"), 0644), ShouldBeNil)
+		So(run(ctx, []string{"-ddl", quoted}, &out), ShouldNotBeNil)
+	})
+}
+
+func TestIdentifiers(t *testing.T) {
+	Convey("next", t, func() {
+		ids := identifiers{"Table": true}
+		So(ids.next("group_id"), ShouldEqual, "GroupId")
+		So(ids.next("createdAt"), ShouldEqual, "CreatedAt")
+		So(ids.next("2fa code"), ShouldEqual, "X2faCode")
+		So(ids.next("table"), ShouldEqual, "Table2")
+		So(ids.next("group-id"), ShouldEqual, "GroupId2")
+		So(ids.next("_"), ShouldEqual, "X")
+	})
+
+	Convey("nextTable", t, func() {
+		ids := identifiers{}
+		So(ids.nextTable("people"), ShouldEqual, "People")
+		So(ids.nextTable("people_row"), ShouldEqual, "PeopleRow2")
+	})
+}
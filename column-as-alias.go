@@ -44,7 +44,7 @@ func (c *cColumnAlias) acceptType(val interface{}) bool {
 
 func (c *cColumnAlias) hasColumn(t *cTable) (present bool) {
 	for _, col := range t.columns {
-		if fncol, ok := c.column.(*cSqlFunc); ok {
+		if fncol, ok := c.column.(iExpression); ok {
 			if present = fncol.hasColumn(t); present {
 				return
 			}
@@ -119,6 +119,26 @@ func (c *cColumnAlias) NotIn(val ...interface{}) Condition {
 	return newInCondition(true, c, val...)
 }
 
+func (c *cColumnAlias) Add(right interface{}) Column {
+	return newExpression("+", c, right)
+}
+
+func (c *cColumnAlias) Sub(right interface{}) Column {
+	return newExpression("-", c, right)
+}
+
+func (c *cColumnAlias) Mul(right interface{}) Column {
+	return newExpression("*", c, right)
+}
+
+func (c *cColumnAlias) Div(right interface{}) Column {
+	return newExpression("/", c, right)
+}
+
+func (c *cColumnAlias) Mod(right interface{}) Column {
+	return newExpression("%", c, right)
+}
+
 func (c *cColumnAlias) Describe() (output string) {
 	// not implemented yet
 	return
@@ -0,0 +1,228 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"strings"
+)
+
+// CaseColumn represents a "CASE WHEN cond THEN value ... ELSE value END"
+// expression, which can be used in the same way as a Column
+type CaseColumn interface {
+	Column
+
+	// When adds a "WHEN cond THEN then" branch. The then is a value, given
+	// as a placeholder argument, or a Column.
+	When(cond Condition, then interface{}) CaseColumn
+	// Else sets the "ELSE value" branch, used when no condition is true. The
+	// value is a value, given as a placeholder argument, or a Column.
+	Else(value interface{}) CaseColumn
+
+	columns() []Column
+}
+
+type cCaseWhen struct {
+	cond Condition
+	then serializable
+}
+
+type cCase struct {
+	whens []cCaseWhen
+	other serializable
+
+	err error
+}
+
+// Case returns a new CASE expression, the branches of which are added with
+// When and Else.
+func Case() CaseColumn {
+	return &cCase{}
+}
+
+func toCaseValue(value interface{}) serializable {
+	if col, ok := value.(Column); ok {
+		return col
+	}
+	return toLiteral(value)
+}
+
+func (c *cCase) When(cond Condition, then interface{}) CaseColumn {
+	if c.err != nil {
+		return c
+	}
+	if cond == nil {
+		c.err = newError("condition of WHEN is nil.")
+		return c
+	}
+	c.whens = append(c.whens, cCaseWhen{
+		cond: cond,
+		then: toCaseValue(then),
+	})
+	return c
+}
+
+func (c *cCase) Else(value interface{}) CaseColumn {
+	if c.err != nil {
+		return c
+	}
+	c.other = toCaseValue(value)
+	return c
+}
+
+func (c *cCase) As(alias string) Column {
+	return &cColumnAlias{
+		column: c,
+		alias:  alias,
+	}
+}
+
+func (c *cCase) table_name() string {
+	return ""
+}
+
+func (c *cCase) column_name() string {
+	return "CASE"
+}
+
+func (c *cCase) config() ColumnConfig {
+	return nil
+}
+
+func (c *cCase) acceptType(interface{}) bool {
+	return false
+}
+
+func (c *cCase) serialize(b *builder) {
+	if c.err != nil {
+		b.SetError(c.err)
+		return
+	}
+	if len(c.whens) == 0 {
+		b.SetError(newError("CASE needs at least one WHEN branch."))
+		return
+	}
+	b.Append("CASE")
+	for _, when := range c.whens {
+		b.Append(" WHEN ")
+		b.AppendItem(when.cond)
+		b.Append(" THEN ")
+		b.AppendItem(when.then)
+	}
+	if c.other != nil {
+		b.Append(" ELSE ")
+		b.AppendItem(c.other)
+	}
+	b.Append(" END")
+}
+
+func (c *cCase) hasColumn(t *cTable) bool {
+	return hasExpressionColumn(t, c.columns())
+}
+
+func (c *cCase) columns() []Column {
+	var columns []Column
+	for _, when := range c.whens {
+		columns = append(columns, when.cond.columns()...)
+		if col, ok := when.then.(Column); ok {
+			columns = append(columns, col)
+		}
+	}
+	if col, ok := c.other.(Column); ok {
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+func (c *cCase) Eq(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, "=")
+}
+
+func (c *cCase) NotEq(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, "<>")
+}
+
+func (c *cCase) Gt(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, ">")
+}
+
+func (c *cCase) GtEq(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, ">=")
+}
+
+func (c *cCase) Lt(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, "<")
+}
+
+func (c *cCase) LtEq(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, "<=")
+}
+
+func (c *cCase) Like(right string) Condition {
+	return newBinaryOperationCondition(c, right, " LIKE ")
+}
+
+func (c *cCase) NotLike(right string) Condition {
+	return newBinaryOperationCondition(c, right, " NOT LIKE ")
+}
+
+func (c *cCase) Between(lower, higher interface{}) Condition {
+	return newBetweenCondition(c, lower, higher)
+}
+
+func (c *cCase) In(vals ...interface{}) Condition {
+	return newInCondition(false, c, vals...)
+}
+
+func (c *cCase) NotIn(vals ...interface{}) Condition {
+	return newInCondition(true, c, vals...)
+}
+
+func (c *cCase) Add(right interface{}) Column {
+	return newExpression("+", c, right)
+}
+
+func (c *cCase) Sub(right interface{}) Column {
+	return newExpression("-", c, right)
+}
+
+func (c *cCase) Mul(right interface{}) Column {
+	return newExpression("*", c, right)
+}
+
+func (c *cCase) Div(right interface{}) Column {
+	return newExpression("/", c, right)
+}
+
+func (c *cCase) Mod(right interface{}) Column {
+	return newExpression("%", c, right)
+}
+
+func (c *cCase) Describe() (output string) {
+	var parts []string
+	for _, when := range c.whens {
+		parts = append(parts, "WHEN "+when.cond.Describe()+" THEN "+when.then.Describe())
+	}
+	if c.other != nil {
+		parts = append(parts, "ELSE "+c.other.Describe())
+	}
+	return "CASE " + strings.Join(parts, " ") + " END"
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"testing"
+)
+
+func TestCase(t *testing.T) {
+	table1 := NewTable(
+		"TABLE_A",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		IntColumn("test1", nil),
+		StringColumn("test2", nil),
+	)
+	table2 := NewTable("TABLE_B", &TableOption{}, IntColumn("id", nil))
+
+	level := Case().
+		When(table1.C("test1").Gt(10), "high").
+		When(table1.C("test1").Gt(5), table1.C("test2")).
+		Else("low")
+
+	var cases = []statementTestCase{{
+		stmt:   Select(table1).Columns(table1.C("id"), level.As("level")),
+		query:  `SELECT "TABLE_A"."id", CASE WHEN "TABLE_A"."test1">? THEN ? WHEN "TABLE_A"."test1">? THEN "TABLE_A"."test2" ELSE ? END AS "level" FROM "TABLE_A";`,
+		args:   []interface{}{int64(10), "high", int64(5), "low"},
+		errmsg: "",
+	}, {
+		stmt: Select(table1).Columns(
+			Func("SUM", Case().When(table1.C("test2").Eq("a"), 1).Else(0)).As("count_a"),
+		).GroupBy(table1.C("test1")),
+		query:  `SELECT SUM(CASE WHEN "TABLE_A"."test2"=? THEN ? ELSE ? END) AS "count_a" FROM "TABLE_A" GROUP BY "TABLE_A"."test1";`,
+		args:   []interface{}{"a", int64(1), int64(0)},
+		errmsg: "",
+	}, {
+		stmt: Select(table1).
+			Where(Case().When(table1.C("test2").Eq(nil), table1.C("id")).Else(table1.C("test1")).Eq(1)).
+			OrderBy(false, Case().When(table1.C("id").Eq(1), 0).Else(1)),
+		query: `SELECT * FROM "TABLE_A" WHERE CASE WHEN "TABLE_A"."test2" IS NULL THEN "TABLE_A"."id" ELSE "TABLE_A"."test1" END=? ` +
+			`ORDER BY CASE WHEN "TABLE_A"."id"=? THEN ? ELSE ? END ASC;`,
+		args:   []interface{}{int64(1), int64(1), int64(0), int64(1)},
+		errmsg: "",
+	}, {
+		stmt:   Select(table1).GroupBy(Case().When(table1.C("test1").Lt(0), "negative").Else("positive")),
+		query:  `SELECT * FROM "TABLE_A" GROUP BY CASE WHEN "TABLE_A"."test1"<? THEN ? ELSE ? END;`,
+		args:   []interface{}{int64(0), "negative", "positive"},
+		errmsg: "",
+	}, {
+		stmt:   Select(table1).Columns(Case().When(table2.C("id").Eq(1), 1)),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: `sqlbuilder: column not found in FROM: "CASE"`,
+	}, {
+		stmt:   Select(table1).Columns(Case().Else(1)),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: CASE needs at least one WHEN branch.",
+	}, {
+		stmt:   Select(table1).Columns(Case().When(nil, 1)),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: condition of WHEN is nil.",
+	}}
+	for num, c := range cases {
+		mes, args, ok := c.Run()
+		if !ok {
+			t.Errorf(mes+" (case no.%d)", append(args, num)...)
+		}
+	}
+}
@@ -112,6 +112,26 @@ func (c *cErrorColumn) NotIn(val ...interface{}) Condition {
 	return newInCondition(true, c, val...)
 }
 
+func (c *cErrorColumn) Add(interface{}) Column {
+	return c
+}
+
+func (c *cErrorColumn) Sub(interface{}) Column {
+	return c
+}
+
+func (c *cErrorColumn) Mul(interface{}) Column {
+	return c
+}
+
+func (c *cErrorColumn) Div(interface{}) Column {
+	return c
+}
+
+func (c *cErrorColumn) Mod(interface{}) Column {
+	return c
+}
+
 func (c *cErrorColumn) Describe() (output string) {
 	// not implemented yet
 	return
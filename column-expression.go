@@ -0,0 +1,218 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"strings"
+)
+
+// cExpression represents an arithmetic or string expression of columns and
+// values, which can be used in the same way as a Column
+type cExpression struct {
+	operator string
+	operands []serializable
+	err      error
+}
+
+func newExpression(operator string, operands ...interface{}) *cExpression {
+	c := &cExpression{
+		operator: operator,
+		operands: make([]serializable, 0, len(operands)),
+	}
+	for _, operand := range operands {
+		if col, ok := operand.(Column); ok {
+			c.operands = append(c.operands, col)
+		} else {
+			c.operands = append(c.operands, toLiteral(operand))
+		}
+	}
+	return c
+}
+
+// Neg creates an expression for "-column".
+func Neg(column Column) Column {
+	if column == nil {
+		return newErrorColumn(newError("column is nil."))
+	}
+	return newExpression("-", column)
+}
+
+// Concat creates an expression concatenating the strings of the values, with
+// the "||" operator or the CONCAT() function depending on the dialect. Type
+// for values is string or Column.
+func Concat(values ...interface{}) Column {
+	c := newExpression("||", values...)
+	if len(values) == 0 {
+		c.err = newError("concatenation needs at least one value.")
+	}
+	return c
+}
+
+func (c *cExpression) table_name() string {
+	return ""
+}
+
+func (c *cExpression) column_name() string {
+	return c.operator
+}
+
+func (c *cExpression) config() ColumnConfig {
+	return nil
+}
+
+func (c *cExpression) acceptType(interface{}) bool {
+	return false
+}
+
+func (c *cExpression) serialize(b *builder) {
+	if c.err != nil {
+		b.SetError(c.err)
+		return
+	}
+	switch {
+	case c.operator == "||":
+		prefix, separator, suffix := b.dialect.ConcatSyntax()
+		b.Append(prefix)
+		for idx, operand := range c.operands {
+			if idx > 0 {
+				b.Append(separator)
+			}
+			serializeOperand(b, operand)
+		}
+		b.Append(suffix)
+	case len(c.operands) == 1:
+		b.Append(c.operator)
+		serializeOperand(b, c.operands[0])
+	default:
+		serializeOperand(b, c.operands[0])
+		b.Append(c.operator)
+		serializeOperand(b, c.operands[1])
+	}
+}
+
+// serializeOperand writes the operand of an expression, within parentheses
+// when it is an expression itself
+func serializeOperand(b *builder, operand serializable) {
+	if _, ok := operand.(*cExpression); ok {
+		b.Append("(")
+		b.AppendItem(operand)
+		b.Append(")")
+		return
+	}
+	b.AppendItem(operand)
+}
+
+func (c *cExpression) hasColumn(t *cTable) bool {
+	return hasExpressionColumn(t, c.columns())
+}
+
+func (c *cExpression) columns() []Column {
+	var columns []Column
+	for _, operand := range c.operands {
+		if col, ok := operand.(Column); ok {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+func (c *cExpression) As(alias string) Column {
+	return &cColumnAlias{
+		column: c,
+		alias:  alias,
+	}
+}
+
+func (c *cExpression) Add(right interface{}) Column {
+	return newExpression("+", c, right)
+}
+
+func (c *cExpression) Sub(right interface{}) Column {
+	return newExpression("-", c, right)
+}
+
+func (c *cExpression) Mul(right interface{}) Column {
+	return newExpression("*", c, right)
+}
+
+func (c *cExpression) Div(right interface{}) Column {
+	return newExpression("/", c, right)
+}
+
+func (c *cExpression) Mod(right interface{}) Column {
+	return newExpression("%", c, right)
+}
+
+func (c *cExpression) Eq(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, "=")
+}
+
+func (c *cExpression) NotEq(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, "<>")
+}
+
+func (c *cExpression) Gt(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, ">")
+}
+
+func (c *cExpression) GtEq(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, ">=")
+}
+
+func (c *cExpression) Lt(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, "<")
+}
+
+func (c *cExpression) LtEq(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, "<=")
+}
+
+func (c *cExpression) Like(right string) Condition {
+	return newBinaryOperationCondition(c, right, " LIKE ")
+}
+
+func (c *cExpression) NotLike(right string) Condition {
+	return newBinaryOperationCondition(c, right, " NOT LIKE ")
+}
+
+func (c *cExpression) Between(lower, higher interface{}) Condition {
+	return newBetweenCondition(c, lower, higher)
+}
+
+func (c *cExpression) In(vals ...interface{}) Condition {
+	return newInCondition(false, c, vals...)
+}
+
+func (c *cExpression) NotIn(vals ...interface{}) Condition {
+	return newInCondition(true, c, vals...)
+}
+
+func (c *cExpression) Describe() (output string) {
+	var parts []string
+	for _, operand := range c.operands {
+		parts = append(parts, operand.Describe())
+	}
+	if len(parts) == 1 && c.operator != "||" {
+		return c.operator + parts[0]
+	}
+	return "(" + strings.Join(parts, " "+c.operator+" ") + ")"
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"testing"
+)
+
+type concatDialect struct {
+	TestingDialect
+}
+
+func (concatDialect) ConcatSyntax() (prefix, separator, suffix string) {
+	return "CONCAT(", ", ", ")"
+}
+
+func TestExpression(t *testing.T) {
+	table1 := NewTable(
+		"TABLE_A",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		IntColumn("price", nil),
+		IntColumn("quantity", nil),
+		StringColumn("first", nil),
+		StringColumn("last", nil),
+	)
+	table2 := NewTable("TABLE_B", &TableOption{}, IntColumn("id", nil))
+
+	fullName := Concat(table1.C("first"), " ", table1.C("last"))
+
+	var cases = []statementTestCase{{
+		stmt:   Update(table1).Set(table1.C("quantity"), table1.C("quantity").Add(1)).Where(table1.C("id").Eq(5)),
+		query:  `UPDATE "TABLE_A" SET "quantity"="TABLE_A"."quantity"+? WHERE "TABLE_A"."id"=?;`,
+		args:   []interface{}{int64(1), int64(5)},
+		errmsg: "",
+	}, {
+		stmt:   Select(table1).Columns(table1.C("price").Mul(table1.C("quantity")).As("total"), fullName.As("name")),
+		query:  `SELECT "TABLE_A"."price"*"TABLE_A"."quantity" AS "total", "TABLE_A"."first" || ? || "TABLE_A"."last" AS "name" FROM "TABLE_A";`,
+		args:   []interface{}{" "},
+		errmsg: "",
+	}, {
+		stmt: Select(table1).
+			Columns(table1.C("id")).
+			Where(table1.C("price").Sub(table1.C("quantity")).Div(2).Gt(10)).
+			OrderBy(true, Neg(table1.C("price").Mod(3))),
+		query: `SELECT "TABLE_A"."id" FROM "TABLE_A" WHERE ("TABLE_A"."price"-"TABLE_A"."quantity")/?>? ` +
+			`ORDER BY -("TABLE_A"."price"%?) DESC;`,
+		args:   []interface{}{int64(2), int64(10), int64(3)},
+		errmsg: "",
+	}, {
+		stmt:   Select(table1).Columns(Func("SUM", table1.C("price").Mul(table1.C("quantity"))), Neg(Neg(table1.C("price")))),
+		query:  `SELECT SUM("TABLE_A"."price"*"TABLE_A"."quantity"), -(-"TABLE_A"."price") FROM "TABLE_A";`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt:   Select(table1).Where(fullName.Like("A%")),
+		query:  `SELECT * FROM "TABLE_A" WHERE "TABLE_A"."first" || ? || "TABLE_A"."last" LIKE ?;`,
+		args:   []interface{}{" ", "A%"},
+		errmsg: "",
+	}, {
+		stmt:   selectFn(table1, concatDialect{}).Columns(Concat(table1.C("first"), table1.C("id").Add(1))),
+		query:  `SELECT CONCAT("TABLE_A"."first", ("TABLE_A"."id"+?)) FROM "TABLE_A";`,
+		args:   []interface{}{int64(1)},
+		errmsg: "",
+	}, {
+		stmt:   Select(table1).Columns(table2.C("id").Add(1)),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: `sqlbuilder: column not found in FROM: "+"`,
+	}, {
+		stmt:   Select(table1).Columns(Concat()),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: concatenation needs at least one value.",
+	}}
+	for num, c := range cases {
+		mes, args, ok := c.Run()
+		if !ok {
+			t.Errorf(mes+" (case no.%d)", append(args, num)...)
+		}
+	}
+}
@@ -82,6 +82,12 @@ func (c *cColumnImpl) acceptType(val interface{}) bool {
 func (c *cColumnImpl) serialize(bldr *builder) {
 	if c == Star {
 		bldr.Append("*")
+	} else if bldr.inline {
+		name := c.name
+		if renamed, ok := bldr.renames[name]; ok {
+			name = renamed
+		}
+		bldr.Append(bldr.dialect.QuoteField(name))
 	} else {
 		bldr.Append(bldr.dialect.QuoteField(c.table.Name()) + "." + bldr.dialect.QuoteField(c.name))
 	}
@@ -139,6 +145,26 @@ func (c *cColumnImpl) NotIn(val ...interface{}) Condition {
 	return newInCondition(true, c, val...)
 }
 
+func (c *cColumnImpl) Add(right interface{}) Column {
+	return newExpression("+", c, right)
+}
+
+func (c *cColumnImpl) Sub(right interface{}) Column {
+	return newExpression("-", c, right)
+}
+
+func (c *cColumnImpl) Mul(right interface{}) Column {
+	return newExpression("*", c, right)
+}
+
+func (c *cColumnImpl) Div(right interface{}) Column {
+	return newExpression("/", c, right)
+}
+
+func (c *cColumnImpl) Mod(right interface{}) Column {
+	return newExpression("%", c, right)
+}
+
 func (c *cColumnImpl) Describe() (output string) {
 	// not implemented yet
 	//output = c.opt.Describe()
@@ -65,6 +65,55 @@ type Column interface {
 
 	// NotIn creates Condition for "column NOT IN (values[0], values[1] ...)".  Type for values is column's one or other Column.
 	NotIn(values ...interface{}) Condition
+
+	// Add creates an expression for "column+right".  Type for right is column's one or other Column.
+	Add(right interface{}) Column
+
+	// Sub creates an expression for "column-right".  Type for right is column's one or other Column.
+	Sub(right interface{}) Column
+
+	// Mul creates an expression for "column*right".  Type for right is column's one or other Column.
+	Mul(right interface{}) Column
+
+	// Div creates an expression for "column/right".  Type for right is column's one or other Column.
+	Div(right interface{}) Column
+
+	// Mod creates an expression for "column%right".  Type for right is column's one or other Column.
+	Mod(right interface{}) Column
+}
+
+// iExpression is implemented by the columns computed from other columns, such
+// as functions and CASE expressions
+type iExpression interface {
+	Column
+	columns() []Column
+	hasColumn(t *cTable) bool
+}
+
+// hasExpressionColumn reports whether any of the columns of an expression is
+// a column of the table, expressions without columns being present in any
+// table
+func hasExpressionColumn(t *cTable, columns []Column) bool {
+	if len(columns) == 0 {
+		return true
+	}
+	for _, col := range columns {
+		if col == Star {
+			return true
+		}
+		if expr, ok := col.(iExpression); ok {
+			if expr.hasColumn(t) {
+				return true
+			}
+			continue
+		}
+		for _, tcol := range t.columns {
+			if SameColumn(tcol, col) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func SameColumn(a, b Column) (same bool) {
@@ -39,6 +39,12 @@ func TestColumnImplements(t *testing.T) {
 	if !fnImplColumn(&cColumnAlias{}) {
 		t.Errorf("fail")
 	}
+	if !fnImplColumn(&cCase{}) {
+		t.Errorf("fail")
+	}
+	if !fnImplColumn(&cExpression{}) {
+		t.Errorf("fail")
+	}
 }
 
 func TestColumnOptionImpl(t *testing.T) {
@@ -50,6 +50,10 @@ func newBinaryOperationCondition(left, right interface{}, operator string) *cCon
 	case Column:
 		column_exist = true
 		cond.right = t
+	case Quantified:
+		cond.right = t
+	case selectStatement:
+		cond.right = newSubQueryValue("", t)
 	default:
 		cond.right = toLiteral(t)
 	}
@@ -94,6 +98,9 @@ func (c *cConditionBinaryOp) columns() []Column {
 	if col, ok := c.right.(Column); ok {
 		list = append(list, col)
 	}
+	if sub, ok := c.right.(*cSubQueryValue); ok {
+		list = append(list, sub.columns()...)
+	}
 	return list
 }
 
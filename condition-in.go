@@ -30,6 +30,7 @@ type cConditionIn struct {
 	not  bool
 	left serializable
 	in   []serializable
+	sub  *cSubQueryValue
 }
 
 func newInCondition(not bool, left Column, list ...interface{}) Condition {
@@ -38,6 +39,12 @@ func newInCondition(not bool, left Column, list ...interface{}) Condition {
 		left: left,
 		in:   make([]serializable, 0, len(list)),
 	}
+	if len(list) == 1 {
+		if stat, ok := list[0].(selectStatement); ok {
+			m.sub = newSubQueryValue("", stat)
+			return m
+		}
+	}
 	for _, item := range list {
 		if c, ok := item.(Column); ok {
 			m.in = append(m.in, c)
@@ -51,7 +58,12 @@ func newInCondition(not bool, left Column, list ...interface{}) Condition {
 func (c *cConditionIn) serialize(b *builder) {
 	b.AppendItem(c.left)
 	if c.not {
-		b.Append(" NOT ")
+		b.Append(" NOT")
+	}
+	if c.sub != nil {
+		b.Append(" IN ")
+		b.AppendItem(c.sub)
+		return
 	}
 	b.Append(" IN ( ")
 	b.AppendItems(c.in, ", ")
@@ -68,6 +80,9 @@ func (c *cConditionIn) columns() []Column {
 			list = append(list, col)
 		}
 	}
+	if c.sub != nil {
+		list = append(list, c.sub.columns()...)
+	}
 	return list
 }
 
@@ -76,6 +91,10 @@ func (c *cConditionIn) Describe() (output string) {
 	if c.not {
 		output += " NOT"
 	}
+	if c.sub != nil {
+		output += " IN " + c.sub.Describe()
+		return
+	}
 	output += " IN ("
 	var parts []string
 	for _, in := range c.in {
@@ -0,0 +1,157 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"strings"
+)
+
+// Quantified is a subquery quantified by ANY or ALL, to be compared with a
+// column by the comparison methods of Column (ex: col.Gt(All(q)))
+type Quantified interface {
+	serializable
+	privateQuantified()
+}
+
+// cSubQueryValue is a SELECT statement used as a value within a condition,
+// optionally quantified by ANY or ALL
+type cSubQueryValue struct {
+	stat       selectStatement
+	quantifier string
+	err        error
+}
+
+func newSubQueryValue(quantifier string, q serializable) *cSubQueryValue {
+	v := &cSubQueryValue{
+		quantifier: quantifier,
+	}
+	if q == nil {
+		v.err = newError("subquery is nil.")
+	} else if stat, ok := q.(selectStatement); ok {
+		v.stat = stat
+	} else {
+		v.err = newError("got %T type, but subquery is not a select statement.", q)
+	}
+	return v
+}
+
+// Any returns the subquery quantified by ANY, for comparisons true when they
+// are true for any of the rows selected by q
+func Any(q SelectBuilder) Quantified {
+	return newSubQueryValue("ANY", q)
+}
+
+// All returns the subquery quantified by ALL, for comparisons true when they
+// are true for all of the rows selected by q
+func All(q SelectBuilder) Quantified {
+	return newSubQueryValue("ALL", q)
+}
+
+func (c *cSubQueryValue) privateQuantified() {
+	// nop
+}
+
+func (c *cSubQueryValue) serialize(b *builder) {
+	if c.err != nil {
+		b.SetError(c.err)
+		return
+	}
+	if c.quantifier != "" {
+		if !b.dialect.SupportsAnyAll() {
+			b.SetError(newError("%s is not supported by the %s dialect.", c.quantifier, b.dialect.Name()))
+			return
+		}
+		b.Append(c.quantifier + " ")
+	}
+	b.Append("( ")
+	if s, ok := c.stat.(*cSelect); ok {
+		// correlated subqueries may reference the columns of outer tables
+		s.serializeSelect(b)
+	} else {
+		b.AppendItem(c.stat)
+	}
+	b.Append(" )")
+}
+
+// columns returns the columns of outer tables referenced by the subquery
+func (c *cSubQueryValue) columns() []Column {
+	if s, ok := c.stat.(*cSelect); ok {
+		return s.outer
+	}
+	return nil
+}
+
+func (c *cSubQueryValue) Describe() (output string) {
+	if c.stat == nil {
+		return
+	}
+	output = "(" + c.stat.Describe() + ")"
+	if c.quantifier != "" {
+		output = c.quantifier + " " + output
+	}
+	return
+}
+
+type cConditionExists struct {
+	not bool
+	sub *cSubQueryValue
+}
+
+// Exists creates a condition for "EXISTS ( q )", true when q selects any rows.
+// The q subquery may reference the columns of the tables of the outer
+// statement.
+func Exists(q SelectBuilder) Condition {
+	return &cConditionExists{
+		sub: newSubQueryValue("", q),
+	}
+}
+
+// NotExists creates a condition for "NOT EXISTS ( q )", true when q selects no
+// rows. The q subquery may reference the columns of the tables of the outer
+// statement.
+func NotExists(q SelectBuilder) Condition {
+	return &cConditionExists{
+		not: true,
+		sub: newSubQueryValue("", q),
+	}
+}
+
+func (c *cConditionExists) serialize(b *builder) {
+	if c.not {
+		b.Append("NOT ")
+	}
+	b.Append("EXISTS ")
+	b.AppendItem(c.sub)
+}
+
+func (c *cConditionExists) columns() []Column {
+	return c.sub.columns()
+}
+
+func (c *cConditionExists) Describe() (output string) {
+	var parts []string
+	if c.not {
+		parts = append(parts, "NOT")
+	}
+	parts = append(parts, "EXISTS", c.sub.Describe())
+	return strings.Join(parts, " ")
+}
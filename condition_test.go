@@ -256,3 +256,133 @@ func TestConnectCondition(t *testing.T) {
 		}
 	}
 }
+
+func TestSubqueryCondition(t *testing.T) {
+	people := NewTable(
+		"PEOPLE",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		StringColumn("name", nil),
+		IntColumn("age", nil),
+	)
+	orders := NewTable(
+		"ORDERS",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		IntColumn("person_id", nil),
+		IntColumn("amount", nil),
+	)
+	groups := NewTable("GROUPS", &TableOption{}, IntColumn("id", nil))
+
+	hasOrders := Select(orders).
+		Columns(orders.C("id")).
+		Where(And(orders.C("person_id").Eq(people.C("id")), orders.C("amount").Gt(100)))
+
+	var cases = []statementTestCase{{
+		stmt: Select(people).Columns(people.C("name")).Where(Exists(hasOrders)),
+		query: `SELECT "PEOPLE"."name" FROM "PEOPLE" WHERE EXISTS ( SELECT "ORDERS"."id" FROM "ORDERS" ` +
+			`WHERE "ORDERS"."person_id"="PEOPLE"."id" AND "ORDERS"."amount">? );`,
+		args:   []interface{}{int64(100)},
+		errmsg: "",
+	}, {
+		stmt: Select(people).Where(And(people.C("age").Gt(18), NotExists(hasOrders))),
+		query: `SELECT * FROM "PEOPLE" WHERE "PEOPLE"."age">? AND NOT EXISTS ( SELECT "ORDERS"."id" FROM "ORDERS" ` +
+			`WHERE "ORDERS"."person_id"="PEOPLE"."id" AND "ORDERS"."amount">? );`,
+		args:   []interface{}{int64(18), int64(100)},
+		errmsg: "",
+	}, {
+		stmt: Select(people).Where(people.C("id").In(Select(orders).Columns(orders.C("person_id")).Where(orders.C("amount").Gt(10)))),
+		query: `SELECT * FROM "PEOPLE" WHERE "PEOPLE"."id" IN ( SELECT "ORDERS"."person_id" FROM "ORDERS" ` +
+			`WHERE "ORDERS"."amount">? );`,
+		args:   []interface{}{int64(10)},
+		errmsg: "",
+	}, {
+		stmt:   Select(people).Where(people.C("id").NotIn(Select(orders).Columns(orders.C("person_id")))),
+		query:  `SELECT * FROM "PEOPLE" WHERE "PEOPLE"."id" NOT IN ( SELECT "ORDERS"."person_id" FROM "ORDERS" );`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt: Select(people).Where(people.C("age").Eq(
+			Select(people).Columns(Func("MAX", people.C("age"))),
+		)),
+		query:  `SELECT * FROM "PEOPLE" WHERE "PEOPLE"."age"=( SELECT MAX("PEOPLE"."age") FROM "PEOPLE" );`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt: Select(people).Where(people.C("age").Gt(
+			Select(orders).Columns(Func("COUNT", orders.C("id"))).Where(orders.C("person_id").Eq(people.C("id"))),
+		)),
+		query: `SELECT * FROM "PEOPLE" WHERE "PEOPLE"."age">( SELECT COUNT("ORDERS"."id") FROM "ORDERS" ` +
+			`WHERE "ORDERS"."person_id"="PEOPLE"."id" );`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt: Select(people).Where(people.C("age").GtEq(All(Select(orders).Columns(orders.C("amount"))))).
+			OrderBy(false, people.C("id")),
+		query:  `SELECT * FROM "PEOPLE" WHERE "PEOPLE"."age">=ALL ( SELECT "ORDERS"."amount" FROM "ORDERS" ) ORDER BY "PEOPLE"."id" ASC;`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt:   Select(people).Where(people.C("id").Eq(Any(Select(orders).Columns(orders.C("person_id")).Where(orders.C("amount").Lt(5))))),
+		query:  `SELECT * FROM "PEOPLE" WHERE "PEOPLE"."id"=ANY ( SELECT "ORDERS"."person_id" FROM "ORDERS" WHERE "ORDERS"."amount"<? );`,
+		args:   []interface{}{int64(5)},
+		errmsg: "",
+	}, {
+		stmt: Select(groups).Where(Exists(Select(people).Where(Exists(
+			Select(orders).Where(And(orders.C("person_id").Eq(people.C("id")), orders.C("id").Eq(groups.C("id")))),
+		)))),
+		query: `SELECT * FROM "GROUPS" WHERE EXISTS ( SELECT * FROM "PEOPLE" WHERE EXISTS ( SELECT * FROM "ORDERS" ` +
+			`WHERE "ORDERS"."person_id"="PEOPLE"."id" AND "ORDERS"."id"="GROUPS"."id" ) );`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt:   hasOrders,
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: `sqlbuilder: column not found in FROM: "id"`,
+	}, {
+		stmt:   Select(groups).Where(Exists(hasOrders)),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: `sqlbuilder: column not found in FROM: "id"`,
+	}, {
+		stmt:   Select(people).Where(people.C("id").In(hasOrders.ToSubquery("SQ"))),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: got sqlbuilder.cSubQuery type, but literal is not supporting this.",
+	}, {
+		stmt:   Select(people).Where(Exists(nil)),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: subquery is nil.",
+	}}
+	for num, c := range cases {
+		mes, args, ok := c.Run()
+		if !ok {
+			t.Errorf(mes+" (case no.%d)", append(args, num)...)
+		}
+	}
+
+	cond := people.C("id").Eq(Any(Select(orders).Columns(orders.C("person_id"))))
+	bldr := newBuilder(dialectNoAnyAll{})
+	cond.serialize(bldr)
+	if bldr.Err() == nil || bldr.Err().Error() != "sqlbuilder: ANY is not supported by the noanyall dialect." {
+		t.Errorf("failed\ngot: %v", bldr.Err())
+	}
+}
+
+type dialectNoAnyAll struct {
+	TestingDialect
+}
+
+func (dialectNoAnyAll) Name() string {
+	return "noanyall"
+}
+
+func (dialectNoAnyAll) SupportsAnyAll() bool {
+	return false
+}
@@ -24,6 +24,7 @@ package sqlbuilder
 // CreateIndexBuilder is the Buildable interface wrapping of CreateTable
 type CreateIndexBuilder interface {
 	IfNotExists() CreateIndexBuilder
+	Unique() CreateIndexBuilder
 	Columns(columns ...Column) CreateIndexBuilder
 	Name(name string) CreateIndexBuilder
 	ToSql() (query string, args []interface{}, err error)
@@ -37,6 +38,7 @@ type cCreateIndex struct {
 	columns     []Column
 	name        string
 	ifNotExists bool
+	unique      bool
 
 	err error
 
@@ -81,6 +83,15 @@ func (b *cCreateIndex) IfNotExists() CreateIndexBuilder {
 	return b
 }
 
+// Unique sets "UNIQUE" clause.
+func (b *cCreateIndex) Unique() CreateIndexBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.unique = true
+	return b
+}
+
 // Columns specifies the columns of the index being created. Must be called
 // otherwise ToSQL will return an error
 func (b *cCreateIndex) Columns(columns ...Column) CreateIndexBuilder {
@@ -112,7 +123,11 @@ func (b *cCreateIndex) ToSql() (query string, args []interface{}, err error) {
 		return
 	}
 
-	bldr.Append("CREATE INDEX ")
+	if b.unique {
+		bldr.Append("CREATE UNIQUE INDEX ")
+	} else {
+		bldr.Append("CREATE INDEX ")
+	}
 	if b.ifNotExists {
 		bldr.Append("IF NOT EXISTS ")
 	}
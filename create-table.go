@@ -109,6 +109,10 @@ func (c *cCreateTable) ToSql() (query string, args []interface{}, err error) {
 	b.AppendItem(cCreateTableColumnList(c.table.Columns()))
 
 	// table option
+	if e := c.table.(*cTable).checkOption(); e != nil {
+		b.SetError(e)
+		return
+	}
 	if tabopt, err := c.dialect.TableOptionToString(c.table.Option()); err == nil {
 		if len(tabopt) != 0 {
 			b.Append(", " + tabopt)
@@ -117,6 +121,17 @@ func (c *cCreateTable) ToSql() (query string, args []interface{}, err error) {
 		b.SetError(err)
 	}
 
+	// check constraints
+	for _, check := range c.table.Option().Checks {
+		if expr, e := check.toString(c.dialect); e != nil {
+			b.SetError(e)
+		} else if opt, e := c.dialect.CheckToString(check.Name, expr); e != nil {
+			b.SetError(e)
+		} else {
+			b.Append(", " + opt)
+		}
+	}
+
 	b.Append(" )")
 	return
 }
@@ -69,6 +69,73 @@ func TestCreate(t *testing.T) {
 			Size: 255,
 		}),
 	)
+	table4 := NewTable(
+		"TABLE_D",
+		&TableOption{
+			ForeignKeys: []ForeignKey{{
+				Columns:    []string{"a_id"},
+				RefTable:   "TABLE_A",
+				RefColumns: []string{"id"},
+				OnDelete:   ForeignKeyCascade,
+			}, {
+				Name:       "FK_TABLE_C",
+				Columns:    []string{"c_test1", "c_test2"},
+				RefTable:   "TABLE_C",
+				RefColumns: []string{"test1", "test2"},
+				OnDelete:   ForeignKeySetNull,
+				OnUpdate:   ForeignKeyRestrict,
+			}},
+		},
+		IntColumn("a_id", nil),
+		IntColumn("c_test1", nil),
+		StringColumn("c_test2", nil),
+	)
+	table5 := NewTable(
+		"TABLE_E",
+		&TableOption{
+			ForeignKeys: []ForeignKey{{
+				Columns:  []string{"invalid"},
+				RefTable: "TABLE_A",
+			}},
+		},
+		IntColumn("a_id", nil),
+	)
+	table6 := NewTable(
+		"TABLE_F",
+		&TableOption{
+			PrimaryKey: []string{"a_id", "c_id"},
+		},
+		IntColumn("a_id", nil),
+		IntColumn("c_id", nil),
+		StringColumn("name", nil),
+		FloatColumn("price", nil),
+	)
+	table6.Option().Checks = []Check{{
+		Name:      "CK_PRICE",
+		Condition: And(table6.C("price").Gt(0), table6.C("price").Lt(1000.5)),
+	}, {
+		Condition: table6.C("name").NotEq("it's"),
+	}}
+	table7 := NewTable(
+		"TABLE_G",
+		&TableOption{
+			PrimaryKey: []string{"id"},
+		},
+		IntColumn("id", &ColumnOption{PrimaryKey: true}),
+	)
+	table8 := NewTable(
+		"TABLE_H",
+		nil,
+		IntColumn("a_id", &ColumnOption{PrimaryKey: true}),
+		IntColumn("c_id", &ColumnOption{PrimaryKey: true}),
+	)
+	table9 := NewTable(
+		"TABLE_I",
+		&TableOption{
+			Checks: []Check{{Condition: table1.C("test1").Gt(0)}},
+		},
+		IntColumn("id", nil),
+	)
 	tableJoined := table1.InnerJoin(table2, table1.C("test1").Eq(table2.C("id")))
 	tableZeroColumns := &cTable{
 		name:    "ZERO_TABLE",
@@ -90,11 +157,60 @@ func TestCreate(t *testing.T) {
 		query:  `CREATE TABLE IF NOT EXISTS "TABLE_C" ( "id" INTEGER PRIMARY KEY AUTOINCREMENT, "test1" INTEGER UNIQUE, "test2" TEXT, UNIQUE("test1", "test2") );`,
 		args:   []interface{}{},
 		errmsg: "",
+	}, {
+		stmt: CreateTable(table4),
+		query: `CREATE TABLE "TABLE_D" ( "a_id" INTEGER, "c_test1" INTEGER, "c_test2" TEXT, ` +
+			`FOREIGN KEY ("a_id") REFERENCES "TABLE_A" ("id") ON DELETE CASCADE, ` +
+			`CONSTRAINT "FK_TABLE_C" FOREIGN KEY ("c_test1", "c_test2") REFERENCES "TABLE_C" ("test1", "test2") ON DELETE SET NULL ON UPDATE RESTRICT );`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt: CreateTable(table6),
+		query: `CREATE TABLE "TABLE_F" ( "a_id" INTEGER, "c_id" INTEGER, "name" TEXT, "price" REAL, ` +
+			`PRIMARY KEY ("a_id", "c_id"), ` +
+			`CONSTRAINT "CK_PRICE" CHECK ("price">0 AND "price"<1000.5), ` +
+			`CHECK ("name"<>'it''s') );`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt:   CreateTable(table7),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: primary key is declared by both ColumnOption and TableOption.",
+	}, {
+		stmt:   CreateTable(table8),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: 2 columns are PrimaryKey, use TableOption.PrimaryKey for composite primary keys.",
+	}, {
+		stmt:   CreateTable(table9),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: column TABLE_I.test1 was not found.",
+	}, {
+		stmt:   CreateTable(table5),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: column TABLE_E.invalid was not found.",
+	}, {
+		stmt: CreateTable(NewTable(
+			"TABLE_J",
+			&TableOption{Unique: [][]string{{"id", "missing"}}},
+			IntColumn("id", nil),
+		)),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: column TABLE_J.missing was not found.",
 	}, {
 		stmt:   CreateIndex(table1).Name("I_TABLE_A").IfNotExists().Columns(table1.C("test1"), table1.C("test2")),
 		query:  `CREATE INDEX IF NOT EXISTS "I_TABLE_A" ON "TABLE_A" ( "test1", "test2" );`,
 		args:   []interface{}{},
 		errmsg: "",
+	}, {
+		stmt:   CreateIndex(table1).Name("U_TABLE_A").Unique().Columns(table1.C("test1")),
+		query:  `CREATE UNIQUE INDEX "U_TABLE_A" ON "TABLE_A" ( "test1" );`,
+		args:   []interface{}{},
+		errmsg: "",
 	}, {
 		stmt:   CreateTable(tableZeroColumns),
 		query:  ``,
@@ -24,6 +24,11 @@ package sqlbuilder
 // DeleteBuilder is the Buildable interface wrapping of Delete
 type DeleteBuilder interface {
 	Where(cond Condition) DeleteBuilder
+
+	// Returning sets the columns of the deleted rows to return, all columns
+	// are returned when none are given
+	Returning(columns ...Column) DeleteBuilder
+
 	ToSql() (query string, args []interface{}, err error)
 
 	privateDelete()
@@ -31,8 +36,9 @@ type DeleteBuilder interface {
 
 // cDelete represents a DELETE statement.
 type cDelete struct {
-	from  Table
-	where Condition
+	from      Table
+	where     Condition
+	returning cSelectColumnList
 
 	err error
 
@@ -83,6 +89,16 @@ func (b *cDelete) Where(cond Condition) DeleteBuilder {
 	return b
 }
 
+// Returning sets RETURNING clause. Returns all columns (use *) if none are
+// given.
+func (b *cDelete) Returning(columns ...Column) DeleteBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.returning, b.err = checkReturning(b.from, columns)
+	return b
+}
+
 // ToSql generates query string, placeholder arguments, and returns err on errors.
 func (b *cDelete) ToSql() (query string, args []interface{}, err error) {
 	bldr := newBuilder(b.dialect)
@@ -101,5 +117,8 @@ func (b *cDelete) ToSql() (query string, args []interface{}, err error) {
 		bldr.Append(" WHERE ")
 		bldr.AppendItem(b.where)
 	}
+
+	// RETURNING
+	serializeReturning(bldr, b.returning)
 	return
 }
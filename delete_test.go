@@ -49,6 +49,16 @@ func TestDelete(t *testing.T) {
 		query:  `DELETE FROM "TABLE_A" WHERE "TABLE_A"."id"=?;`,
 		args:   []interface{}{int64(1)},
 		errmsg: "",
+	}, {
+		stmt:   Delete(table1).Where(table1.C("id").Eq(1)).Returning(),
+		query:  `DELETE FROM "TABLE_A" WHERE "TABLE_A"."id"=? RETURNING *;`,
+		args:   []interface{}{int64(1)},
+		errmsg: "",
+	}, {
+		stmt:   Delete(table1).Returning(table1.C("test2"), table2.C("id")),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: column not found in table: \"id\"",
 	}, {
 		stmt:   Delete(nil).Where(table1.C("id").Eq(1)),
 		query:  ``,
@@ -22,8 +22,10 @@
 package sqlbuilder
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -73,6 +75,10 @@ func (td TestingDialect) QuoteField(field interface{}) string {
 	return str
 }
 
+func (td TestingDialect) QuoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
 func (td TestingDialect) ColumnTypeToString(cc ColumnConfig) (string, error) {
 	if cc.Option().SqlType != "" {
 		return cc.Option().SqlType, nil
@@ -139,9 +145,32 @@ func (td TestingDialect) TableOptionToString(to *TableOption) (string, error) {
 		return str
 	}
 
+	if len(to.PrimaryKey) != 0 {
+		opt = apnd(opt, "PRIMARY KEY (")
+		for idx, col := range to.PrimaryKey {
+			if idx > 0 {
+				opt += ", "
+			}
+			opt += td.QuoteField(col)
+		}
+		opt += ")"
+	}
 	if to.Unique != nil {
+		if len(opt) != 0 {
+			opt += ","
+		}
 		opt = apnd(opt, td.tableOptionUnique(to.Unique))
 	}
+	for idx := range to.ForeignKeys {
+		fk, err := td.ForeignKeyToString(&to.ForeignKeys[idx])
+		if err != nil {
+			return "", err
+		}
+		if len(opt) != 0 {
+			opt += ", "
+		}
+		opt += fk
+	}
 	return opt, nil
 }
 
@@ -166,3 +195,184 @@ func (td TestingDialect) tableOptionUnique(op [][]string) string {
 	}
 	return opt
 }
+
+func (td TestingDialect) SupportsCompound(operator string) bool {
+	return true
+}
+
+func (td TestingDialect) MaxBindVars() int {
+	return 0
+}
+
+func (td TestingDialect) OnConflictToString(columns []string, doNothing bool) (string, error) {
+	opt := "ON CONFLICT ("
+	for idx, col := range columns {
+		if idx > 0 {
+			opt += ", "
+		}
+		opt += td.QuoteField(col)
+	}
+	if doNothing {
+		return opt + ") DO NOTHING", nil
+	}
+	return opt + ") DO UPDATE SET", nil
+}
+
+func (td TestingDialect) ExcludedColumnToString(column string) string {
+	return "excluded." + td.QuoteField(column)
+}
+
+func (td TestingDialect) SupportsReturning() bool {
+	return true
+}
+
+func (td TestingDialect) SupportsAnyAll() bool {
+	return true
+}
+
+func (td TestingDialect) ConcatSyntax() (prefix, separator, suffix string) {
+	return "", " || ", ""
+}
+
+func (td TestingDialect) FuncToString(name string, args []string) (string, error) {
+	switch name {
+	case "DATE_TRUNC", "EXTRACT":
+		if len(args) != 2 {
+			return "", newError("%s function needs 2 arguments, got %d.", name, len(args))
+		}
+		if name == "DATE_TRUNC" {
+			return "DATE_TRUNC('" + strings.ToLower(args[0]) + "', " + args[1] + ")", nil
+		}
+		return "EXTRACT(" + args[0] + " FROM " + args[1] + ")", nil
+	}
+	return name + "(" + strings.Join(args, ", ") + ")", nil
+}
+
+func (td TestingDialect) ForeignKeyToString(fk *ForeignKey) (string, error) {
+	for _, action := range []ForeignKeyAction{fk.OnDelete, fk.OnUpdate} {
+		if !action.Valid() {
+			return "", newError("unknown foreign key action %s.", action)
+		}
+	}
+	quoteList := func(names []string) string {
+		opt := "("
+		for idx, name := range names {
+			if idx > 0 {
+				opt += ", "
+			}
+			opt += td.QuoteField(name)
+		}
+		return opt + ")"
+	}
+
+	opt := ""
+	if len(fk.Name) != 0 {
+		opt += "CONSTRAINT " + td.QuoteField(fk.Name) + " "
+	}
+	opt += "FOREIGN KEY " + quoteList(fk.Columns)
+	opt += " REFERENCES " + td.QuoteField(fk.RefTable)
+	if len(fk.RefColumns) != 0 {
+		opt += " " + quoteList(fk.RefColumns)
+	}
+	if fk.OnDelete != ForeignKeyNoAction {
+		opt += " ON DELETE " + fk.OnDelete.String()
+	}
+	if fk.OnUpdate != ForeignKeyNoAction {
+		opt += " ON UPDATE " + fk.OnUpdate.String()
+	}
+	return opt, nil
+}
+
+func (td TestingDialect) AlterForeignKeyToString(fk *ForeignKey, drop bool) (string, error) {
+	if drop {
+		return "DROP CONSTRAINT " + td.QuoteField(fk.Name), nil
+	}
+	opt, err := td.ForeignKeyToString(fk)
+	if err != nil {
+		return "", err
+	}
+	return "ADD " + opt, nil
+}
+
+func (td TestingDialect) CheckToString(name, expression string) (string, error) {
+	opt := ""
+	if len(name) != 0 {
+		opt += "CONSTRAINT " + td.QuoteField(name) + " "
+	}
+	return opt + "CHECK (" + expression + ")", nil
+}
+
+func (td TestingDialect) BeginToString(isolation sql.IsolationLevel, readOnly bool) ([]string, error) {
+	query := "BEGIN"
+	if isolation != sql.LevelDefault {
+		query += " ISOLATION LEVEL " + strings.ToUpper(isolation.String())
+	}
+	if readOnly {
+		query += " READ ONLY"
+	}
+	return []string{query}, nil
+}
+
+func (td TestingDialect) SavepointToString(action SavepointAction, name string) (string, error) {
+	return action.String() + " " + td.QuoteField(name), nil
+}
+
+func (td TestingDialect) TableExistsToString() string {
+	return `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ` + td.BindVar(1)
+}
+
+func (td TestingDialect) AlterTableToString(table string, actions []AlterTableAction) ([]string, error) {
+	columnDefinition := func(cc ColumnConfig) (string, error) {
+		typ, err := td.ColumnTypeToString(cc)
+		if err != nil {
+			return "", err
+		}
+		opt, err := td.ColumnOptionToString(cc.Option())
+		if err != nil {
+			return "", err
+		} else if len(opt) != 0 {
+			typ += " " + opt
+		}
+		return td.QuoteField(cc.Name()) + " " + typ, nil
+	}
+	position := func(action AlterTableAction) string {
+		if action.First {
+			return " FIRST"
+		} else if len(action.After) != 0 {
+			return " AFTER " + td.QuoteField(action.After)
+		}
+		return ""
+	}
+
+	stmt := "ALTER TABLE " + td.QuoteField(table) + " "
+	for idx, action := range actions {
+		if idx > 0 {
+			stmt += ", "
+		}
+		switch action.Type {
+		case AlterTableAddColumn:
+			def, err := columnDefinition(action.Column)
+			if err != nil {
+				return nil, err
+			}
+			stmt += "ADD COLUMN " + def + position(action)
+		case AlterTableChangeColumn:
+			def, err := columnDefinition(action.Column)
+			if err != nil {
+				return nil, err
+			}
+			stmt += "CHANGE COLUMN " + td.QuoteField(action.OldColumn.Name()) + " " + def + position(action)
+		case AlterTableDropColumn:
+			stmt += "DROP COLUMN " + td.QuoteField(action.OldColumn.Name())
+		case AlterTableAddForeignKey, AlterTableDropForeignKey:
+			opt, err := td.AlterForeignKeyToString(action.ForeignKey, action.Type == AlterTableDropForeignKey)
+			if err != nil {
+				return nil, err
+			}
+			stmt += opt
+		case AlterTableRename:
+			stmt += "RENAME TO " + td.QuoteField(action.Name)
+		}
+	}
+	return []string{stmt}, nil
+}
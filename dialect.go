@@ -21,6 +21,10 @@
 
 package sqlbuilder
 
+import (
+	"database/sql"
+)
+
 var _dialect Dialect = nil
 
 // Dialect encapsulates behaviors that differ across SQL database.
@@ -29,9 +33,58 @@ type Dialect interface {
 	QuerySuffix() string
 	BindVar(i int) string
 	QuoteField(field interface{}) string
+	// QuoteString returns the string literal of the value, for the values
+	// written inline such as within CHECK constraints
+	QuoteString(value string) string
 	ColumnTypeToString(ColumnConfig) (string, error)
 	ColumnOptionToString(*ColumnOption) (string, error)
 	TableOptionToString(*TableOption) (string, error)
+	// SupportsCompound reports whether the compound select operator given
+	// (UNION, UNION ALL, INTERSECT or EXCEPT) is supported
+	SupportsCompound(operator string) bool
+	// MaxBindVars returns the maximum number of bind variables allowed in a
+	// single statement, zero for no limit
+	MaxBindVars() int
+	// OnConflictToString returns the upsert clause for the conflict target
+	// columns given, either skipping the insert or leading a list of
+	// column=value assignments
+	OnConflictToString(columns []string, doNothing bool) (string, error)
+	// ExcludedColumnToString returns a reference to the value proposed for
+	// insertion into the named column, for use within the upsert clause
+	ExcludedColumnToString(column string) string
+	// SupportsReturning reports whether INSERT, UPDATE and DELETE statements
+	// support the RETURNING clause
+	SupportsReturning() bool
+	// SupportsAnyAll reports whether comparisons with a subquery quantified
+	// by ANY or ALL are supported
+	SupportsAnyAll() bool
+	// ConcatSyntax returns the prefix, the separator and the suffix joining
+	// string expressions into their concatenation
+	ConcatSyntax() (prefix, separator, suffix string)
+	// FuncToString returns the call of the function of the function library
+	// named (ex: "LENGTH", see Length) with the arguments given, in order as
+	// they may hold bind variables. The date part is the first argument of
+	// "DATE_TRUNC" and "EXTRACT".
+	FuncToString(name string, args []string) (string, error)
+	// ForeignKeyToString returns the table constraint clause of the foreign
+	// key, for use within CREATE TABLE statements
+	ForeignKeyToString(*ForeignKey) (string, error)
+	// AlterTableToString returns the statements applying the actions to the
+	// table named, in order
+	AlterTableToString(table string, actions []AlterTableAction) ([]string, error)
+	// CheckToString returns the table constraint clause of a CHECK
+	// constraint, the name is optional
+	CheckToString(name, expression string) (string, error)
+	// BeginToString returns the statements beginning a transaction with the
+	// isolation level given (sql.LevelDefault for the database default),
+	// optionally rejecting changes
+	BeginToString(isolation sql.IsolationLevel, readOnly bool) ([]string, error)
+	// SavepointToString returns the statement starting, releasing or rolling
+	// back to the savepoint named
+	SavepointToString(action SavepointAction, name string) (string, error)
+	// TableExistsToString returns the query counting the tables of the
+	// current database named by its only bind variable
+	TableExistsToString() string
 }
 
 // SetDialect sets dialect for SQL server.
@@ -22,6 +22,11 @@
 package dialects
 
 import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
 	"github.com/go-corelibs/go-sqlbuilder"
 )
 
@@ -46,3 +51,186 @@ func Parse(name string) (d sqlbuilder.Dialect, ok bool) {
 	}
 	return
 }
+
+// foreignKeyToString returns the standard "FOREIGN KEY" table constraint
+// clause, quoting names with the dialect given
+func foreignKeyToString(d sqlbuilder.Dialect, fk *sqlbuilder.ForeignKey) (string, error) {
+	if len(fk.Columns) == 0 {
+		return "", errors.New("dialects: foreign key columns are required")
+	}
+	if len(fk.RefTable) == 0 {
+		return "", errors.New("dialects: foreign key referenced table is required")
+	}
+	if len(fk.RefColumns) != 0 && len(fk.RefColumns) != len(fk.Columns) {
+		return "", errors.New("dialects: foreign key columns and referenced columns differ in number")
+	}
+	for _, action := range []sqlbuilder.ForeignKeyAction{fk.OnDelete, fk.OnUpdate} {
+		if !action.Valid() {
+			return "", errors.New("dialects: unknown foreign key action " + action.String())
+		}
+	}
+	quoteList := func(names []string) string {
+		opt := "("
+		for idx, name := range names {
+			if idx > 0 {
+				opt += ", "
+			}
+			opt += d.QuoteField(name)
+		}
+		return opt + ")"
+	}
+
+	opt := ""
+	if len(fk.Name) != 0 {
+		opt += "CONSTRAINT " + d.QuoteField(fk.Name) + " "
+	}
+	opt += "FOREIGN KEY " + quoteList(fk.Columns)
+	opt += " REFERENCES " + d.QuoteField(fk.RefTable)
+	if len(fk.RefColumns) != 0 {
+		opt += " " + quoteList(fk.RefColumns)
+	}
+	if fk.OnDelete != sqlbuilder.ForeignKeyNoAction {
+		opt += " ON DELETE " + fk.OnDelete.String()
+	}
+	if fk.OnUpdate != sqlbuilder.ForeignKeyNoAction {
+		opt += " ON UPDATE " + fk.OnUpdate.String()
+	}
+	return opt, nil
+}
+
+// quoteString returns the standard string literal of the value, doubling its
+// quotes
+func quoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// tableOptionPrimaryKey returns the "PRIMARY KEY" table constraint clause
+func tableOptionPrimaryKey(d sqlbuilder.Dialect, columns []string) string {
+	opt := "PRIMARY KEY ("
+	for idx, col := range columns {
+		if idx > 0 {
+			opt += ", "
+		}
+		opt += d.QuoteField(col)
+	}
+	return opt + ")"
+}
+
+// checkToString returns the standard "CHECK" table constraint clause
+func checkToString(d sqlbuilder.Dialect, name, expression string) (string, error) {
+	if len(expression) == 0 {
+		return "", errors.New("dialects: check expression is required")
+	}
+	opt := ""
+	if len(name) != 0 {
+		opt += "CONSTRAINT " + d.QuoteField(name) + " "
+	}
+	return opt + "CHECK (" + expression + ")", nil
+}
+
+// isolationLevelToString returns the standard name of the isolation level
+func isolationLevelToString(level sql.IsolationLevel) (string, error) {
+	switch level {
+	case sql.LevelReadUncommitted, sql.LevelReadCommitted, sql.LevelRepeatableRead, sql.LevelSerializable:
+		return strings.ToUpper(level.String()), nil
+	}
+	return "", errors.New("dialects: isolation level " + level.String() + " is not supported")
+}
+
+// savepointToString returns the standard savepoint statement
+func savepointToString(d sqlbuilder.Dialect, action sqlbuilder.SavepointAction, name string) (string, error) {
+	if len(name) == 0 {
+		return "", errors.New("dialects: savepoint name is required")
+	}
+	return action.String() + " " + d.QuoteField(name), nil
+}
+
+// tableOptionForeignKeys appends the foreign key clauses of the TableOption
+// to the table constraints given
+func tableOptionForeignKeys(d sqlbuilder.Dialect, opt string, to *sqlbuilder.TableOption) (string, error) {
+	for idx := range to.ForeignKeys {
+		fk, err := d.ForeignKeyToString(&to.ForeignKeys[idx])
+		if err != nil {
+			return "", err
+		}
+		if len(opt) != 0 {
+			opt += ", "
+		}
+		opt += fk
+	}
+	return opt, nil
+}
+
+// funcToString returns the call of the library function named, in the form
+// shared by the dialects
+func funcToString(name string, args []string) (string, error) {
+	switch name {
+	case "COALESCE", "NULLIF", "LOWER", "UPPER", "LENGTH", "ROUND", "ABS", "NOW":
+		return name + "(" + strings.Join(args, ", ") + ")", nil
+	case "EXTRACT":
+		if err := funcArgs(name, args, 2); err != nil {
+			return "", err
+		}
+		return "EXTRACT(" + args[0] + " FROM " + args[1] + ")", nil
+	}
+	return "", errors.New("dialects: " + name + " function is not supported")
+}
+
+// funcArgs returns an error unless the function is given n arguments
+func funcArgs(name string, args []string, n int) error {
+	if len(args) != n {
+		return errors.New("dialects: " + name + " function needs " + strconv.Itoa(n) + " arguments, got " + strconv.Itoa(len(args)))
+	}
+	return nil
+}
+
+// columnDefinition returns the quoted name, type and options of the column
+// given, as used by ALTER TABLE statements
+func columnDefinition(d sqlbuilder.Dialect, cc sqlbuilder.ColumnConfig) (string, error) {
+	typ, err := d.ColumnTypeToString(cc)
+	if err != nil {
+		return "", err
+	} else if len(typ) == 0 {
+		return "", errors.New("dialects: column type is required")
+	}
+	opt, err := d.ColumnOptionToString(cc.Option())
+	if err != nil {
+		return "", err
+	} else if len(opt) != 0 {
+		typ += " " + opt
+	}
+	return d.QuoteField(cc.Name()) + " " + typ, nil
+}
+
+// versionAtLeast reports whether the version given is empty or is greater than
+// or equal to the minimum version, comparing the leading numbers of each
+// dot-separated component in turn (ie: "8.0.31-log" is at least "8.0")
+func versionAtLeast(version, minimum string) bool {
+	if version == "" {
+		return true
+	}
+	have := strings.Split(version, ".")
+	want := strings.Split(minimum, ".")
+	for idx, w := range want {
+		var h string
+		if idx < len(have) {
+			h = have[idx]
+		}
+		hv, wv := leadingNumber(h), leadingNumber(w)
+		if hv != wv {
+			return hv > wv
+		}
+	}
+	return true
+}
+
+func leadingNumber(value string) (number int) {
+	end := strings.IndexFunc(value, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	if end >= 0 {
+		value = value[:end]
+	}
+	number, _ = strconv.Atoi(value)
+	return
+}
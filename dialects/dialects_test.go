@@ -22,6 +22,7 @@
 package dialects
 
 import (
+	"fmt"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -57,6 +58,306 @@ func TestParse(t *testing.T) {
 	})
 }
 
+func TestCommonTables(t *testing.T) {
+	nodes := sqlbuilder.NewTable(
+		"NODES", nil,
+		sqlbuilder.IntColumn("id", &sqlbuilder.ColumnOption{PrimaryKey: true}),
+		sqlbuilder.IntColumn("parent_id", nil),
+	)
+	tb := sqlbuilder.NewBuildable(sqlbuilder.TestingDialect{})
+	tree := sqlbuilder.WithRecursive("TREE",
+		tb.Select(nodes).
+			Columns(nodes.C("id"), nodes.C("parent_id")).
+			Where(nodes.C("id").Eq(1)),
+		func(self sqlbuilder.Table) sqlbuilder.SelectBuilder {
+			return tb.Select(nodes.InnerJoin(self, nodes.C("parent_id").Eq(self.C("id")))).
+				Columns(nodes.C("id"), nodes.C("parent_id"))
+		},
+	)
+
+	Convey("WITH RECURSIVE", t, func() {
+		for idx, test := range []struct {
+			d     sqlbuilder.Dialect
+			query string
+		}{
+			{
+				MySql{},
+				"WITH RECURSIVE `TREE` AS ( " +
+					"SELECT `NODES`.`id`, `NODES`.`parent_id` FROM `NODES` WHERE `NODES`.`id`=? UNION ALL " +
+					"SELECT `NODES`.`id`, `NODES`.`parent_id` FROM `NODES` INNER JOIN `TREE` ON `NODES`.`parent_id`=`TREE`.`id` " +
+					") SELECT `TREE`.`id` FROM `TREE` WHERE `TREE`.`parent_id`=?;",
+			},
+			{
+				Postgresql{},
+				`WITH RECURSIVE "TREE" AS ( ` +
+					`SELECT "NODES"."id", "NODES"."parent_id" FROM "NODES" WHERE "NODES"."id"=$1 UNION ALL ` +
+					`SELECT "NODES"."id", "NODES"."parent_id" FROM "NODES" INNER JOIN "TREE" ON "NODES"."parent_id"="TREE"."id" ` +
+					`) SELECT "TREE"."id" FROM "TREE" WHERE "TREE"."parent_id"=$2;`,
+			},
+			{
+				Sqlite{},
+				`WITH RECURSIVE "TREE" AS ( ` +
+					`SELECT "NODES"."id", "NODES"."parent_id" FROM "NODES" WHERE "NODES"."id"=? UNION ALL ` +
+					`SELECT "NODES"."id", "NODES"."parent_id" FROM "NODES" INNER JOIN "TREE" ON "NODES"."parent_id"="TREE"."id" ` +
+					`) SELECT "TREE"."id" FROM "TREE" WHERE "TREE"."parent_id"=?;`,
+			},
+		} {
+			Convey(fmt.Sprintf("case #%d", idx), func() {
+				query, args, err := sqlbuilder.NewBuildable(test.d).
+					Select(tree).
+					Columns(tree.C("id")).
+					Where(tree.C("parent_id").Eq(2)).
+					ToSql()
+				So(err, ShouldBeNil)
+				So(args, ShouldEqual, []interface{}{int64(1), int64(2)})
+				So(query, ShouldEqual, test.query)
+			})
+		}
+	})
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	Convey("versionAtLeast", t, func() {
+		for idx, test := range []struct {
+			version string
+			minimum string
+			ok      bool
+		}{
+			{"", "8.0.31", true},
+			{"8.0.31", "8.0.31", true},
+			{"8.0.30", "8.0.31", false},
+			{"8.1", "8.0.31", true},
+			{"5.7.44-log", "8.0.31", false},
+			{"10.6.12-MariaDB", "8.0.31", true},
+			{"8", "8.0.31", false},
+		} {
+			Convey(fmt.Sprintf("case #%d", idx), func() {
+				So(versionAtLeast(test.version, test.minimum), ShouldEqual, test.ok)
+			})
+		}
+	})
+}
+
+func TestCompoundSelect(t *testing.T) {
+	tbl := sqlbuilder.NewTable(
+		"TABLE_A", nil,
+		sqlbuilder.IntColumn("id", &sqlbuilder.ColumnOption{PrimaryKey: true}),
+		sqlbuilder.IntColumn("test1", nil),
+	)
+
+	Convey("Postgresql bind variables", t, func() {
+		b := sqlbuilder.NewBuildable(Postgresql{})
+		query, args, err := sqlbuilder.Union(
+			b.Select(tbl).Columns(tbl.C("id")).Where(tbl.C("test1").Eq(1)),
+			b.Select(tbl).Columns(tbl.C("id")).Where(tbl.C("test1").Eq(2)),
+		).Limit(5).ToSql()
+		So(err, ShouldBeNil)
+		So(args, ShouldEqual, []interface{}{int64(1), int64(2), 5})
+		So(query, ShouldEqual, `SELECT "TABLE_A"."id" FROM "TABLE_A" WHERE "TABLE_A"."test1"=$1 `+
+			`UNION SELECT "TABLE_A"."id" FROM "TABLE_A" WHERE "TABLE_A"."test1"=$2 LIMIT $3;`)
+	})
+
+	Convey("MySql versions", t, func() {
+		for idx, test := range []struct {
+			d   MySql
+			op  string
+			err Assertion
+		}{
+			{MySql{}, "INTERSECT", ShouldBeNil},
+			{MySql{Version: "8.0.31"}, "EXCEPT", ShouldBeNil},
+			{MySql{Version: "8.0.30"}, "UNION", ShouldBeNil},
+			{MySql{Version: "8.0.30"}, "INTERSECT", ShouldNotBeNil},
+			{MySql{Version: "5.7"}, "EXCEPT", ShouldNotBeNil},
+		} {
+			Convey(fmt.Sprintf("case #%d", idx), func() {
+				b := sqlbuilder.NewBuildable(test.d)
+				a, c := b.Select(tbl).Columns(tbl.C("id")), b.Select(tbl).Columns(tbl.C("test1"))
+				var stmt sqlbuilder.CompoundSelectBuilder
+				switch test.op {
+				case "UNION":
+					stmt = sqlbuilder.Union(a, c)
+				case "INTERSECT":
+					stmt = sqlbuilder.Intersect(a, c)
+				case "EXCEPT":
+					stmt = sqlbuilder.Except(a, c)
+				}
+				_, _, err := stmt.ToSql()
+				So(err, test.err)
+			})
+		}
+	})
+}
+
+func TestReturning(t *testing.T) {
+	tbl := sqlbuilder.NewTable(
+		"TABLE_A", nil,
+		sqlbuilder.IntColumn("id", &sqlbuilder.ColumnOption{PrimaryKey: true}),
+		sqlbuilder.StringColumn("name", nil),
+	)
+
+	Convey("Postgresql", t, func() {
+		query, args, err := sqlbuilder.NewBuildable(Postgresql{}).
+			Insert(tbl).
+			Set(tbl.C("name"), "thing").
+			Returning(tbl.C("id")).
+			ToSql()
+		So(err, ShouldBeNil)
+		So(args, ShouldEqual, []interface{}{"thing"})
+		So(query, ShouldEqual, `INSERT INTO "TABLE_A" ( "name" ) VALUES ( $1 ) RETURNING "TABLE_A"."id";`)
+	})
+
+	Convey("MySql", t, func() {
+		_, _, err := sqlbuilder.NewBuildable(MySql{}).
+			Delete(tbl).
+			Returning(tbl.C("id")).
+			ToSql()
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, "sqlbuilder: RETURNING is not supported by the mysql dialect.")
+	})
+}
+
+func TestInsertSelect(t *testing.T) {
+	tbl := sqlbuilder.NewTable(
+		"TABLE_A", nil,
+		sqlbuilder.IntColumn("id", &sqlbuilder.ColumnOption{PrimaryKey: true}),
+		sqlbuilder.StringColumn("name", nil),
+	)
+	archive := sqlbuilder.NewTable(
+		"ARCHIVE", nil,
+		sqlbuilder.IntColumn("id", &sqlbuilder.ColumnOption{PrimaryKey: true}),
+		sqlbuilder.StringColumn("name", nil),
+	)
+
+	Convey("Postgresql bind variables", t, func() {
+		b := sqlbuilder.NewBuildable(Postgresql{})
+		query, args, err := b.Insert(archive).
+			Columns(archive.C("id"), archive.C("name")).
+			FromSelect(b.Select(tbl).
+				Columns(tbl.C("id"), tbl.C("name")).
+				Where(tbl.C("name").Like("old%"))).
+			Returning(archive.C("id")).
+			ToSql()
+		So(err, ShouldBeNil)
+		So(args, ShouldEqual, []interface{}{"old%"})
+		So(query, ShouldEqual, `INSERT INTO "ARCHIVE" ( "id", "name" ) `+
+			`SELECT "TABLE_A"."id", "TABLE_A"."name" FROM "TABLE_A" WHERE "TABLE_A"."name" LIKE $1 `+
+			`RETURNING "ARCHIVE"."id";`)
+	})
+}
+
+// alterQueries returns the queries of the statements of the AlterTable
+func alterQueries(alter sqlbuilder.AlterTableBuilder) (queries []string) {
+	statements, err := alter.Statements()
+	So(err, ShouldBeNil)
+	for _, stmt := range statements {
+		query, _, err := stmt.ToSql()
+		So(err, ShouldBeNil)
+		queries = append(queries, query)
+	}
+	return
+}
+
+func TestAlterTable(t *testing.T) {
+	tbl := sqlbuilder.NewTable(
+		"TABLE_A", nil,
+		sqlbuilder.IntColumn("id", &sqlbuilder.ColumnOption{PrimaryKey: true}),
+		sqlbuilder.StringColumn("name", &sqlbuilder.ColumnOption{Size: 255}),
+		sqlbuilder.IntColumn("count", nil),
+	)
+
+	Convey("MySql", t, func() {
+		b := sqlbuilder.NewBuildable(MySql{})
+		query, _, err := b.AlterTable(tbl).
+			AddColumnAfter(sqlbuilder.IntColumn("total", nil), tbl.C("name")).
+			ChangeColumn(tbl.C("name"), sqlbuilder.StringColumn("title", &sqlbuilder.ColumnOption{Size: 100, NotNull: true})).
+			DropColumn(tbl.C("count")).
+			RenameTo("TABLE_B").
+			ToSql()
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, "ALTER TABLE `TABLE_A` ADD COLUMN `total` INTEGER AFTER `name`, "+
+			"CHANGE COLUMN `name` `title` VARCHAR(100) NOT NULL, DROP COLUMN `count`, RENAME TO `TABLE_B`;")
+	})
+
+	Convey("Postgresql", t, func() {
+		b := sqlbuilder.NewBuildable(Postgresql{})
+		alter := b.AlterTable(tbl).
+			AddColumn(sqlbuilder.IntColumn("total", nil)).
+			ChangeColumn(tbl.C("name"), sqlbuilder.StringColumn("title", &sqlbuilder.ColumnOption{Size: 100, NotNull: true, Default: "none"})).
+			DropColumn(tbl.C("count")).
+			RenameTo("TABLE_B")
+		So(alterQueries(alter), ShouldResemble, []string{
+			`ALTER TABLE "TABLE_A" RENAME COLUMN "name" TO "title";`,
+			`ALTER TABLE "TABLE_A" ADD COLUMN "total" BIGINT DEFAULT NULL, ` +
+				`ALTER COLUMN "title" TYPE VARCHAR(100), ALTER COLUMN "title" SET NOT NULL, ALTER COLUMN "title" SET DEFAULT 'none', ` +
+				`DROP COLUMN "count";`,
+			`ALTER TABLE "TABLE_A" RENAME TO "TABLE_B";`,
+		})
+		_, _, err := alter.ToSql()
+		So(err.Error(), ShouldEqual, "sqlbuilder: AlterTable needs 3 statements in the postgresql dialect. (see AlterTableBuilder.Statements)")
+
+		query, _, err := b.AlterTable(tbl).
+			ChangeColumn(tbl.C("count"), sqlbuilder.IntColumn("count", &sqlbuilder.ColumnOption{Unique: true})).
+			ToSql()
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, `ALTER TABLE "TABLE_A" ALTER COLUMN "count" TYPE BIGINT, `+
+			`ALTER COLUMN "count" DROP NOT NULL, ALTER COLUMN "count" DROP DEFAULT, ADD UNIQUE ("count");`)
+
+		_, _, err = b.AlterTable(tbl).AddColumnFirst(sqlbuilder.IntColumn("total", nil)).ToSql()
+		So(err, ShouldNotBeNil)
+		_, _, err = b.AlterTable(tbl).
+			ChangeColumn(tbl.C("id"), sqlbuilder.IntColumn("id", &sqlbuilder.ColumnOption{PrimaryKey: true, AutoIncrement: true})).
+			ToSql()
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Sqlite", t, func() {
+		b := sqlbuilder.NewBuildable(Sqlite{})
+		alter := b.AlterTable(tbl).
+			AddColumn(sqlbuilder.IntColumn("total", nil)).
+			ChangeColumn(tbl.C("name"), sqlbuilder.StringColumn("title", &sqlbuilder.ColumnOption{Size: 255})).
+			DropColumn(tbl.C("count")).
+			RenameTo("TABLE_B")
+		So(alterQueries(alter), ShouldResemble, []string{
+			`ALTER TABLE "TABLE_A" ADD COLUMN "total" INTEGER DEFAULT NULL;`,
+			`ALTER TABLE "TABLE_A" RENAME COLUMN "name" TO "title";`,
+			`ALTER TABLE "TABLE_A" DROP COLUMN "count";`,
+			`ALTER TABLE "TABLE_A" RENAME TO "TABLE_B";`,
+		})
+		_, _, err := alter.ToSql()
+		So(err, ShouldNotBeNil)
+
+		_, _, err = b.AlterTable(tbl).
+			ChangeColumn(tbl.C("name"), sqlbuilder.IntColumn("name", nil)).
+			ToSql()
+		So(err, ShouldNotBeNil)
+		_, _, err = b.AlterTable(tbl).AddColumnAfter(sqlbuilder.IntColumn("total", nil), tbl.C("id")).ToSql()
+		So(err, ShouldNotBeNil)
+		_, _, err = b.AlterTable(tbl).AddColumn(sqlbuilder.IntColumn("total", &sqlbuilder.ColumnOption{Unique: true})).ToSql()
+		So(err, ShouldNotBeNil)
+		_, _, err = sqlbuilder.NewBuildable(Sqlite{Version: "3.34.1"}).AlterTable(tbl).DropColumn(tbl.C("count")).ToSql()
+		So(err, ShouldNotBeNil)
+		_, _, err = sqlbuilder.NewBuildable(Sqlite{Version: "3.24.0"}).AlterTable(tbl).
+			ChangeColumn(tbl.C("name"), sqlbuilder.StringColumn("title", &sqlbuilder.ColumnOption{Size: 255})).
+			ToSql()
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Sqlite rebuild", t, func() {
+		b := sqlbuilder.NewBuildable(Sqlite{})
+		queries, err := b.AlterTable(tbl).
+			ChangeColumn(tbl.C("count"), sqlbuilder.FloatColumn("count", &sqlbuilder.ColumnOption{NotNull: true, Default: 0})).
+			Rebuild(b.CreateIndex(tbl).Name("I_NAME").Columns(tbl.C("name")))
+		So(err, ShouldBeNil)
+		So(queries, ShouldEqual, []string{
+			`CREATE TABLE "new_TABLE_A" ( "id" INTEGER PRIMARY KEY, "name" TEXT DEFAULT NULL, "count" REAL NOT NULL DEFAULT "0" );`,
+			`INSERT INTO "new_TABLE_A" ( "id", "name", "count" ) SELECT "TABLE_A"."id", "TABLE_A"."name", "TABLE_A"."count" FROM "TABLE_A";`,
+			`DROP TABLE "TABLE_A";`,
+			`ALTER TABLE "new_TABLE_A" RENAME TO "TABLE_A";`,
+			`CREATE INDEX "I_NAME" ON "TABLE_A" ( "name" );`,
+		})
+	})
+}
+
 var (
 	gTestInputs = []string{
 		`ALTER TABLE "TABLE_A" ADD COLUMN "test0" INTEGER AFTER "id";`,
@@ -0,0 +1,144 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dialects
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	sb "github.com/go-corelibs/go-sqlbuilder"
+)
+
+func (m MySql) ColumnTypeFromString(sqlType string) (sb.ColumnType, *sb.ColumnOption) {
+	opt := &sb.ColumnOption{SqlType: strings.ToUpper(strings.TrimSpace(sqlType))}
+	base := opt.SqlType
+	if idx := strings.IndexAny(base, "( "); idx >= 0 {
+		base = base[:idx]
+	}
+	typ := sb.ColumnTypeAny
+	switch base {
+	case "BOOL", "BOOLEAN":
+		typ = sb.ColumnTypeBool
+	case "TINYINT":
+		// BOOLEAN is a synonym of TINYINT(1)
+		if typ = sb.ColumnTypeInt; typeSize(opt.SqlType) == 1 {
+			typ = sb.ColumnTypeBool
+			opt.SqlType = ""
+		}
+	case "INT", "INTEGER":
+		// the display width is deprecated, ie: INT(11)
+		typ = sb.ColumnTypeInt
+		if !strings.Contains(opt.SqlType, "UNSIGNED") && !strings.Contains(opt.SqlType, "ZEROFILL") {
+			opt.SqlType = ""
+		}
+	case "SMALLINT", "MEDIUMINT", "BIGINT":
+		typ = sb.ColumnTypeInt
+	case "VARCHAR", "CHAR", "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT":
+		typ = sb.ColumnTypeString
+		opt.Size = typeSize(opt.SqlType)
+	case "DATETIME", "DATE", "TIMESTAMP", "TIME":
+		typ = sb.ColumnTypeDate
+	case "FLOAT", "DOUBLE", "REAL", "DECIMAL", "NUMERIC":
+		typ = sb.ColumnTypeFloat
+	case "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BINARY", "VARBINARY":
+		typ = sb.ColumnTypeBytes
+	}
+	return typ, impliedSqlType(m, typ, opt)
+}
+
+func (m MySql) TableNames(ctx context.Context, db sb.Querier) ([]string, error) {
+	return queryStrings(ctx, db, `SELECT TABLE_NAME FROM information_schema.TABLES `+
+		`WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME`)
+}
+
+func (m MySql) IntrospectTable(ctx context.Context, db sb.Querier, name string) (sb.Table, error) {
+	columns, err := m.introspectColumns(ctx, db, name)
+	if err != nil {
+		return nil, err
+	}
+	primary, unique, err := m.introspectKeys(ctx, db, name)
+	if err != nil {
+		return nil, err
+	}
+	return introspectedTable(m, name, columns, primary, unique)
+}
+
+func (m MySql) introspectColumns(ctx context.Context, db sb.Querier, name string) ([]introspectedColumn, error) {
+	rows, err := db.QueryContext(ctx, `SELECT COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT, EXTRA `+
+		`FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var columns []introspectedColumn
+	for rows.Next() {
+		var col introspectedColumn
+		var nullable, extra string
+		var def sql.NullString
+		if err = rows.Scan(&col.name, &col.sqlType, &nullable, &def, &extra); err != nil {
+			return nil, err
+		}
+		col.notNull = nullable == "NO"
+		extra = strings.ToUpper(extra)
+		col.autoinc = strings.Contains(extra, "AUTO_INCREMENT")
+		if def.Valid && !strings.Contains(extra, "DEFAULT_GENERATED") {
+			col.defaultSql = &def.String
+			// mysql gives literal defaults unquoted, mariadb quotes them
+			if value := def.String; !strings.HasPrefix(value, "'") && !strings.EqualFold(value, "NULL") && !strings.HasSuffix(value, ")") &&
+				!strings.EqualFold(value, "CURRENT_TIMESTAMP") {
+				value = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+				col.defaultSql = &value
+			}
+		}
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+func (m MySql) introspectKeys(ctx context.Context, db sb.Querier, name string) (primary []string, unique [][]string, err error) {
+	rows, err := db.QueryContext(ctx, `SELECT tc.CONSTRAINT_NAME, tc.CONSTRAINT_TYPE, kcu.COLUMN_NAME `+
+		`FROM information_schema.TABLE_CONSTRAINTS tc JOIN information_schema.KEY_COLUMN_USAGE kcu `+
+		`ON kcu.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND kcu.CONSTRAINT_NAME = tc.CONSTRAINT_NAME AND kcu.TABLE_NAME = tc.TABLE_NAME `+
+		`WHERE tc.TABLE_SCHEMA = DATABASE() AND tc.TABLE_NAME = ? AND tc.CONSTRAINT_TYPE IN ('PRIMARY KEY', 'UNIQUE') `+
+		`ORDER BY tc.CONSTRAINT_NAME, kcu.ORDINAL_POSITION`, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	last := ""
+	for rows.Next() {
+		var constraint, kind, column string
+		if err = rows.Scan(&constraint, &kind, &column); err != nil {
+			return nil, nil, err
+		}
+		if kind == "PRIMARY KEY" {
+			primary = append(primary, column)
+		} else if constraint == last {
+			unique[len(unique)-1] = append(unique[len(unique)-1], column)
+		} else {
+			unique = append(unique, []string{column})
+		}
+		last = constraint
+	}
+	return primary, unique, rows.Err()
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dialects
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	sb "github.com/go-corelibs/go-sqlbuilder"
+)
+
+func (m Postgresql) ColumnTypeFromString(sqlType string) (sb.ColumnType, *sb.ColumnOption) {
+	opt := &sb.ColumnOption{SqlType: strings.ToUpper(strings.TrimSpace(sqlType))}
+	base := opt.SqlType
+	if idx := strings.IndexByte(base, '('); idx >= 0 {
+		base = strings.TrimSpace(base[:idx])
+	}
+	typ := sb.ColumnTypeAny
+	switch base {
+	case "BOOLEAN", "BOOL":
+		typ = sb.ColumnTypeBool
+	case "BIGINT", "INT8", "INTEGER", "INT", "INT4", "SMALLINT", "INT2", "SERIAL", "BIGSERIAL", "SMALLSERIAL":
+		typ = sb.ColumnTypeInt
+	case "CHARACTER VARYING", "VARCHAR", "CHARACTER", "CHAR", "TEXT":
+		typ = sb.ColumnTypeString
+		opt.Size = typeSize(opt.SqlType)
+		if base == "CHARACTER VARYING" && opt.Size > 0 {
+			opt.SqlType = ""
+		}
+	case "TIMESTAMP WITHOUT TIME ZONE", "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "TIMESTAMPTZ", "DATE",
+		"TIME WITHOUT TIME ZONE", "TIME", "TIME WITH TIME ZONE":
+		typ = sb.ColumnTypeDate
+		if base == "TIMESTAMP WITHOUT TIME ZONE" {
+			opt.SqlType = ""
+		}
+	case "REAL", "FLOAT4", "DOUBLE PRECISION", "FLOAT8", "NUMERIC", "DECIMAL":
+		typ = sb.ColumnTypeFloat
+	case "BYTEA":
+		typ = sb.ColumnTypeBytes
+	}
+	return typ, impliedSqlType(m, typ, opt)
+}
+
+func (m Postgresql) TableNames(ctx context.Context, db sb.Querier) ([]string, error) {
+	return queryStrings(ctx, db, `SELECT c.relname FROM pg_catalog.pg_class c `+
+		`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace `+
+		`WHERE c.relkind IN ('r', 'p') AND n.nspname = current_schema() ORDER BY c.relname`)
+}
+
+func (m Postgresql) IntrospectTable(ctx context.Context, db sb.Querier, name string) (sb.Table, error) {
+	columns, err := m.introspectColumns(ctx, db, name)
+	if err != nil {
+		return nil, err
+	}
+	primary, unique, err := m.introspectKeys(ctx, db, name)
+	if err != nil {
+		return nil, err
+	}
+	return introspectedTable(m, name, columns, primary, unique)
+}
+
+func (m Postgresql) introspectColumns(ctx context.Context, db sb.Querier, name string) ([]introspectedColumn, error) {
+	rows, err := db.QueryContext(ctx, `SELECT a.attname, pg_catalog.format_type(a.atttypid, a.atttypmod), a.attnotnull, `+
+		`pg_catalog.pg_get_expr(d.adbin, d.adrelid), a.attidentity <> '' `+
+		`FROM pg_catalog.pg_attribute a `+
+		`JOIN pg_catalog.pg_class c ON c.oid = a.attrelid `+
+		`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace `+
+		`LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum `+
+		`WHERE c.relname = $1 AND n.nspname = current_schema() AND a.attnum > 0 AND NOT a.attisdropped `+
+		`ORDER BY a.attnum`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var columns []introspectedColumn
+	for rows.Next() {
+		var col introspectedColumn
+		var def sql.NullString
+		if err = rows.Scan(&col.name, &col.sqlType, &col.notNull, &def, &col.autoinc); err != nil {
+			return nil, err
+		}
+		if def.Valid {
+			if strings.HasPrefix(def.String, "nextval(") {
+				// SERIAL columns default to the next value of their sequence
+				col.autoinc = true
+			} else {
+				col.defaultSql = &def.String
+			}
+		}
+		if col.autoinc && col.sqlType == "integer" {
+			// SERIAL columns are NOT NULL, which is implied
+			col.sqlType, col.notNull = "SERIAL", false
+		} else if col.autoinc && col.sqlType == "bigint" {
+			col.sqlType, col.notNull = "BIGSERIAL", false
+		}
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+func (m Postgresql) introspectKeys(ctx context.Context, db sb.Querier, name string) (primary []string, unique [][]string, err error) {
+	rows, err := db.QueryContext(ctx, `SELECT con.conname, con.contype, a.attname `+
+		`FROM pg_catalog.pg_constraint con `+
+		`JOIN pg_catalog.pg_class c ON c.oid = con.conrelid `+
+		`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace `+
+		`CROSS JOIN LATERAL unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord) `+
+		`JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid AND a.attnum = k.attnum `+
+		`WHERE c.relname = $1 AND n.nspname = current_schema() AND con.contype IN ('p', 'u') `+
+		`ORDER BY con.conname, k.ord`, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	last := ""
+	for rows.Next() {
+		var constraint, kind, column string
+		if err = rows.Scan(&constraint, &kind, &column); err != nil {
+			return nil, nil, err
+		}
+		if kind == "p" {
+			primary = append(primary, column)
+		} else if constraint == last {
+			unique[len(unique)-1] = append(unique[len(unique)-1], column)
+		} else {
+			unique = append(unique, []string{column})
+		}
+		last = constraint
+	}
+	return primary, unique, rows.Err()
+}
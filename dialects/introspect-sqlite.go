@@ -0,0 +1,140 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dialects
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	sb "github.com/go-corelibs/go-sqlbuilder"
+)
+
+func (m Sqlite) ColumnTypeFromString(sqlType string) (sb.ColumnType, *sb.ColumnOption) {
+	opt := &sb.ColumnOption{SqlType: strings.ToUpper(strings.TrimSpace(sqlType))}
+	typ := sb.ColumnTypeAny
+	// in the order of the sqlite type affinity rules
+	switch upper := opt.SqlType; {
+	case strings.Contains(upper, "BOOL"):
+		typ = sb.ColumnTypeBool
+	case strings.Contains(upper, "DATE"), strings.Contains(upper, "TIME"):
+		typ = sb.ColumnTypeDate
+	case strings.Contains(upper, "INT"):
+		typ = sb.ColumnTypeInt
+	case strings.Contains(upper, "CHAR"), strings.Contains(upper, "CLOB"), strings.Contains(upper, "TEXT"):
+		typ = sb.ColumnTypeString
+		opt.Size = typeSize(upper)
+	case upper == "", strings.Contains(upper, "BLOB"):
+		typ = sb.ColumnTypeBytes
+	case strings.Contains(upper, "REAL"), strings.Contains(upper, "FLOA"), strings.Contains(upper, "DOUB"),
+		strings.Contains(upper, "NUMERIC"), strings.Contains(upper, "DECIMAL"):
+		typ = sb.ColumnTypeFloat
+	}
+	return typ, impliedSqlType(m, typ, opt)
+}
+
+func (m Sqlite) TableNames(ctx context.Context, db sb.Querier) ([]string, error) {
+	return queryStrings(ctx, db, `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite\_%' ESCAPE '\' ORDER BY name`)
+}
+
+func (m Sqlite) IntrospectTable(ctx context.Context, db sb.Querier, name string) (sb.Table, error) {
+	var ddl sql.NullString
+	err := db.QueryRowContext(ctx, `SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&ddl)
+	if err == sql.ErrNoRows {
+		return introspectedTable(m, name, nil, nil, nil)
+	} else if err != nil {
+		return nil, err
+	}
+
+	columns, primary, err := m.introspectTableInfo(ctx, db, name)
+	if err != nil {
+		return nil, err
+	}
+	// only an INTEGER PRIMARY KEY column can be AUTOINCREMENT
+	if len(primary) == 1 && strings.Contains(strings.ToUpper(ddl.String), "AUTOINCREMENT") {
+		for idx := range columns {
+			if columns[idx].name == primary[0] {
+				columns[idx].autoinc = true
+			}
+		}
+	}
+
+	unique, err := m.introspectUnique(ctx, db, name)
+	if err != nil {
+		return nil, err
+	}
+	return introspectedTable(m, name, columns, primary, unique)
+}
+
+func (m Sqlite) introspectColumns(ctx context.Context, db sb.Querier, name string) ([]introspectedColumn, error) {
+	columns, _, err := m.introspectTableInfo(ctx, db, name)
+	return columns, err
+}
+
+func (m Sqlite) introspectTableInfo(ctx context.Context, db sb.Querier, name string) (columns []introspectedColumn, primary []string, err error) {
+	rows, err := db.QueryContext(ctx, `SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_info(?) ORDER BY cid`, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	keys := map[int]string{}
+	for rows.Next() {
+		var col introspectedColumn
+		var def sql.NullString
+		var pk int
+		if err = rows.Scan(&col.name, &col.sqlType, &col.notNull, &def, &pk); err != nil {
+			return nil, nil, err
+		}
+		if def.Valid {
+			col.defaultSql = &def.String
+		}
+		if pk > 0 {
+			keys[pk] = col.name
+		}
+		columns = append(columns, col)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	for idx := 1; idx <= len(keys); idx++ {
+		primary = append(primary, keys[idx])
+	}
+	return columns, primary, nil
+}
+
+func (m Sqlite) introspectUnique(ctx context.Context, db sb.Querier, name string) (unique [][]string, err error) {
+	// indexes created by UNIQUE constraints, in the order declared, then the
+	// unique indexes created by sqlbuilder.DiffSchema
+	indexes, err := queryStrings(ctx, db, `SELECT name FROM pragma_index_list(?) `+
+		`WHERE "unique" AND (origin = 'u' OR origin = 'c' AND name LIKE 'uq\_%' ESCAPE '\') ORDER BY origin = 'c', seq DESC`, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		columns, err := queryStrings(ctx, db, `SELECT name FROM pragma_index_info(?) ORDER BY seqno`, index)
+		if err != nil {
+			return nil, err
+		}
+		unique = append(unique, columns)
+	}
+	return unique, nil
+}
@@ -0,0 +1,289 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dialects
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-corelibs/go-sqlbuilder"
+)
+
+// Introspector is implemented by the dialects able to load the tables of a
+// live database
+type Introspector interface {
+	sqlbuilder.Dialect
+
+	// ColumnTypeFromString returns the column type of the SQL type given,
+	// reversing ColumnTypeToString. The option has the Size of the SQL type
+	// and, when the column type does not produce the SQL type given, the
+	// SqlType too
+	ColumnTypeFromString(sqlType string) (sqlbuilder.ColumnType, *sqlbuilder.ColumnOption)
+
+	// TableNames returns the names of the tables of the current database,
+	// in name order
+	TableNames(ctx context.Context, db sqlbuilder.Querier) ([]string, error)
+
+	// IntrospectTable returns the table named, with the column types,
+	// options, defaults, primary key and unique constraints of the database
+	IntrospectTable(ctx context.Context, db sqlbuilder.Querier, name string) (sqlbuilder.Table, error)
+}
+
+// columnIntrospector reads the columns of a table, before their types and
+// defaults are parsed
+type columnIntrospector interface {
+	introspectColumns(ctx context.Context, db sqlbuilder.Querier, name string) ([]introspectedColumn, error)
+}
+
+var (
+	_ Introspector = MySql{}
+	_ Introspector = Postgresql{}
+	_ Introspector = Sqlite{}
+
+	_ columnIntrospector = MySql{}
+	_ columnIntrospector = Postgresql{}
+	_ columnIntrospector = Sqlite{}
+)
+
+// Introspect returns all tables of the current database, in name order
+func Introspect(ctx context.Context, db sqlbuilder.Querier, d sqlbuilder.Dialect) ([]sqlbuilder.Table, error) {
+	in, ok := d.(Introspector)
+	if !ok {
+		return nil, errors.New("dialects: " + d.Name() + " does not support introspection")
+	}
+	names, err := in.TableNames(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]sqlbuilder.Table, 0, len(names))
+	for _, name := range names {
+		tbl, err := in.IntrospectTable(ctx, db, name)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, tbl)
+	}
+	return tables, nil
+}
+
+// UnparsedDefaults returns the SQL of the column defaults of the table named
+// which are not literals, such as CURRENT_TIMESTAMP, by column name. These
+// defaults are left unset by IntrospectTable.
+func UnparsedDefaults(ctx context.Context, db sqlbuilder.Querier, d sqlbuilder.Dialect, name string) (map[string]string, error) {
+	in, ok := d.(interface {
+		Introspector
+		columnIntrospector
+	})
+	if !ok {
+		return nil, errors.New("dialects: " + d.Name() + " does not support introspection")
+	}
+	columns, err := in.introspectColumns(ctx, db, name)
+	if err != nil {
+		return nil, err
+	}
+	defaults := map[string]string{}
+	for _, col := range columns {
+		if col.defaultSql == nil {
+			continue
+		}
+		typ, _ := in.ColumnTypeFromString(col.sqlType)
+		if _, ok = parseDefault(typ, *col.defaultSql); !ok {
+			defaults[col.name] = *col.defaultSql
+		}
+	}
+	return defaults, nil
+}
+
+// introspectedColumn is a column read from the database
+type introspectedColumn struct {
+	name       string
+	sqlType    string
+	notNull    bool
+	defaultSql *string
+	primary    bool
+	unique     bool
+	autoinc    bool
+}
+
+// introspectedTable returns the table of the columns and constraints read
+// from the database, with the column types given by the dialect. Single
+// column primary and unique keys are declared as column options
+func introspectedTable(d Introspector, name string, columns []introspectedColumn, primary []string, unique [][]string) (sqlbuilder.Table, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("dialects: table " + name + " was not found")
+	}
+	option := &sqlbuilder.TableOption{}
+	if len(primary) > 1 {
+		option.PrimaryKey = primary
+	}
+	for _, u := range unique {
+		if len(u) > 1 {
+			option.Unique = append(option.Unique, u)
+		}
+	}
+
+	configs := make([]sqlbuilder.ColumnConfig, 0, len(columns))
+	for _, col := range columns {
+		typ, opt := d.ColumnTypeFromString(col.sqlType)
+		opt.NotNull = col.notNull
+		opt.AutoIncrement = col.autoinc
+		if len(primary) == 1 && primary[0] == col.name {
+			opt.PrimaryKey = true
+		}
+		for _, u := range unique {
+			if len(u) == 1 && u[0] == col.name {
+				opt.Unique = true
+			}
+		}
+		if col.defaultSql != nil {
+			opt.Default, _ = parseDefault(typ, *col.defaultSql)
+		}
+		// the options may imply the SqlType too, ie: SERIAL
+		configs = append(configs, newColumnConfig(col.name, typ, impliedSqlType(d, typ, opt)))
+	}
+	return sqlbuilder.NewTable(name, option, configs...), nil
+}
+
+// newColumnConfig returns the ColumnConfig of the column type given
+func newColumnConfig(name string, typ sqlbuilder.ColumnType, opt *sqlbuilder.ColumnOption) sqlbuilder.ColumnConfig {
+	switch typ {
+	case sqlbuilder.ColumnTypeInt:
+		return sqlbuilder.IntColumn(name, opt)
+	case sqlbuilder.ColumnTypeString:
+		return sqlbuilder.StringColumn(name, opt)
+	case sqlbuilder.ColumnTypeDate:
+		return sqlbuilder.DateColumn(name, opt)
+	case sqlbuilder.ColumnTypeFloat:
+		return sqlbuilder.FloatColumn(name, opt)
+	case sqlbuilder.ColumnTypeBool:
+		return sqlbuilder.BoolColumn(name, opt)
+	case sqlbuilder.ColumnTypeBytes:
+		return sqlbuilder.BytesColumn(name, opt)
+	}
+	return sqlbuilder.AnyColumn(name, opt)
+}
+
+// impliedSqlType clears the SqlType of the option when it is the one the
+// dialect gives the column type and other options
+func impliedSqlType(d sqlbuilder.Dialect, typ sqlbuilder.ColumnType, opt *sqlbuilder.ColumnOption) *sqlbuilder.ColumnOption {
+	if typ == sqlbuilder.ColumnTypeAny || len(opt.SqlType) == 0 {
+		return opt
+	}
+	implied := *opt
+	implied.SqlType = ""
+	if str, err := d.ColumnTypeToString(newColumnConfig("", typ, &implied)); err == nil && strings.EqualFold(str, opt.SqlType) {
+		opt.SqlType = ""
+	}
+	return opt
+}
+
+// queryStrings returns the first column of all rows of the query
+func queryStrings(ctx context.Context, db sqlbuilder.Querier, query string, args ...interface{}) (values []string, err error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var value string
+		if err = rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// typeSize returns the first number within the parentheses of the SQL type
+// given (ie: 64 for "VARCHAR(64)"), or zero
+func typeSize(sqlType string) int {
+	start := strings.IndexByte(sqlType, '(')
+	if start < 0 {
+		return 0
+	}
+	return leadingNumber(strings.TrimSpace(sqlType[start+1:]))
+}
+
+// parseDefault returns the value of the column default given, as SQL text,
+// reporting false when it is not a literal of the column type (such as
+// CURRENT_TIMESTAMP)
+func parseDefault(typ sqlbuilder.ColumnType, text string) (value interface{}, ok bool) {
+	text = strings.TrimSpace(text)
+	for len(text) > 1 && text[0] == '(' && text[len(text)-1] == ')' {
+		text = strings.TrimSpace(text[1 : len(text)-1])
+	}
+	if strings.EqualFold(text, "NULL") {
+		return nil, true
+	}
+
+	quoted := false
+	if len(text) > 1 {
+		if q := text[0]; q == '\'' || q == '"' || q == '`' {
+			// postgres casts the literal, ie: 'one'::character varying
+			if end := strings.LastIndexByte(text, q); end > 0 {
+				text = strings.ReplaceAll(text[1:end], string([]byte{q, q}), string(q))
+				quoted = true
+			}
+		}
+	}
+	if !quoted {
+		if idx := strings.Index(text, "::"); idx > 0 {
+			text = text[:idx]
+		}
+	}
+
+	switch typ {
+	case sqlbuilder.ColumnTypeInt:
+		if v, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return v, true
+		}
+	case sqlbuilder.ColumnTypeFloat:
+		if v, err := strconv.ParseFloat(text, 64); err == nil {
+			return v, true
+		}
+	case sqlbuilder.ColumnTypeBool:
+		switch strings.ToLower(text) {
+		case "true", "t", "1":
+			return true, true
+		case "false", "f", "0":
+			return false, true
+		}
+	case sqlbuilder.ColumnTypeDate:
+		for _, layout := range []string{"2006-01-02 15:04:05", time.RFC3339, "2006-01-02"} {
+			if v, err := time.Parse(layout, text); err == nil {
+				return v, true
+			}
+		}
+	case sqlbuilder.ColumnTypeBytes:
+		if quoted {
+			return []byte(text), true
+		}
+	default:
+		if quoted {
+			return text, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dialects
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/go-corelibs/go-sqlbuilder"
+)
+
+func TestIntrospect(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	d := Sqlite{}
+	b := sqlbuilder.NewBuildable(d)
+
+	people := b.NewTable(
+		"people",
+		&sqlbuilder.TableOption{Unique: [][]string{{"name", "nick"}}},
+		sqlbuilder.IntColumn("id", &sqlbuilder.ColumnOption{PrimaryKey: true, AutoIncrement: true}),
+		sqlbuilder.StringColumn("name", &sqlbuilder.ColumnOption{NotNull: true}),
+		sqlbuilder.StringColumn("nick", &sqlbuilder.ColumnOption{Unique: true}),
+		sqlbuilder.IntColumn("age", &sqlbuilder.ColumnOption{Default: int64(18)}),
+		sqlbuilder.FloatColumn("rate", &sqlbuilder.ColumnOption{Default: 1.5}),
+		sqlbuilder.BoolColumn("admin", &sqlbuilder.ColumnOption{NotNull: true, Default: false}),
+		sqlbuilder.DateColumn("created", nil),
+		sqlbuilder.BytesColumn("data", nil),
+		sqlbuilder.AnyColumn("tags", &sqlbuilder.ColumnOption{SqlType: "JSON"}),
+	)
+	create, _, err := b.CreateTable(people).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, query := range []string{
+		create,
+		`CREATE TABLE "memberships" (
+			"group_id" INTEGER NOT NULL,
+			"person_id" INTEGER NOT NULL,
+			"title" VARCHAR(64) DEFAULT 'it''s',
+			"joined" DATETIME DEFAULT CURRENT_TIMESTAMP,
+			"since" DATE DEFAULT '2024-01-02',
+			PRIMARY KEY ("group_id", "person_id")
+		)`,
+		`CREATE UNIQUE INDEX "memberships_title" ON "memberships" ("title")`,
+	} {
+		if _, err = db.Exec(query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	Convey("TableNames", t, func() {
+		names, err := d.TableNames(ctx, db)
+		So(err, ShouldBeNil)
+		So(names, ShouldResemble, []string{"memberships", "people"})
+	})
+
+	Convey("IntrospectTable", t, func() {
+		tbl, err := d.IntrospectTable(ctx, db, "people")
+		So(err, ShouldBeNil)
+		query, _, err := b.CreateTable(tbl).ToSql()
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, create)
+
+		tbl, err = d.IntrospectTable(ctx, db, "memberships")
+		So(err, ShouldBeNil)
+		So(tbl.Option().PrimaryKey, ShouldResemble, []string{"group_id", "person_id"})
+		So(tbl.Option().Unique, ShouldBeNil)
+		query, _, err = b.CreateTable(tbl).ToSql()
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, `CREATE TABLE "memberships" ( "group_id" INTEGER NOT NULL DEFAULT NULL, `+
+			`"person_id" INTEGER NOT NULL DEFAULT NULL, "title" VARCHAR(64) DEFAULT "it's", "joined" DATETIME DEFAULT NULL, `+
+			`"since" DATE DEFAULT "`+time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC).Format("2006-01-02 15:04:05")+`", `+
+			`PRIMARY KEY ("group_id", "person_id") );`)
+
+		_, err = d.IntrospectTable(ctx, db, "nothing")
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("UnparsedDefaults", t, func() {
+		defaults, err := UnparsedDefaults(ctx, db, d, "memberships")
+		So(err, ShouldBeNil)
+		So(defaults, ShouldResemble, map[string]string{"joined": "CURRENT_TIMESTAMP"})
+
+		_, err = UnparsedDefaults(ctx, db, sqlbuilder.TestingDialect{}, "memberships")
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Introspect", t, func() {
+		tables, err := Introspect(ctx, db, d)
+		So(err, ShouldBeNil)
+		So(len(tables), ShouldEqual, 2)
+		So(tables[0].Name(), ShouldEqual, "memberships")
+		So(tables[1].Name(), ShouldEqual, "people")
+
+		_, err = Introspect(ctx, db, sqlbuilder.TestingDialect{})
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("ColumnTypeFromString", t, func() {
+		for _, test := range []struct {
+			d       Introspector
+			input   string
+			typ     sqlbuilder.ColumnType
+			size    int
+			sqlType string
+		}{
+			{Sqlite{}, "INTEGER", sqlbuilder.ColumnTypeInt, 0, ""},
+			{Sqlite{}, "bigint", sqlbuilder.ColumnTypeInt, 0, "BIGINT"},
+			{Sqlite{}, "varchar(64)", sqlbuilder.ColumnTypeString, 64, "VARCHAR(64)"},
+			{Sqlite{}, "DATETIME", sqlbuilder.ColumnTypeDate, 0, ""},
+			{Sqlite{}, "JSON", sqlbuilder.ColumnTypeAny, 0, "JSON"},
+			{MySql{}, "int(11)", sqlbuilder.ColumnTypeInt, 0, ""},
+			{MySql{}, "int unsigned", sqlbuilder.ColumnTypeInt, 0, "INT UNSIGNED"},
+			{MySql{}, "tinyint(1)", sqlbuilder.ColumnTypeBool, 0, ""},
+			{MySql{}, "varchar(255)", sqlbuilder.ColumnTypeString, 255, ""},
+			{MySql{}, "text", sqlbuilder.ColumnTypeString, 0, "TEXT"},
+			{MySql{}, "double", sqlbuilder.ColumnTypeFloat, 0, "DOUBLE"},
+			{Postgresql{}, "bigint", sqlbuilder.ColumnTypeInt, 0, ""},
+			{Postgresql{}, "integer", sqlbuilder.ColumnTypeInt, 0, "INTEGER"},
+			{Postgresql{}, "character varying(32)", sqlbuilder.ColumnTypeString, 32, ""},
+			{Postgresql{}, "timestamp without time zone", sqlbuilder.ColumnTypeDate, 0, ""},
+			{Postgresql{}, "bytea", sqlbuilder.ColumnTypeBytes, 0, ""},
+			{Postgresql{}, "jsonb", sqlbuilder.ColumnTypeAny, 0, "JSONB"},
+		} {
+			typ, opt := test.d.ColumnTypeFromString(test.input)
+			So(typ, ShouldEqual, test.typ)
+			So(opt.Size, ShouldEqual, test.size)
+			So(opt.SqlType, ShouldEqual, test.sqlType)
+		}
+	})
+}
+
+func TestIntrospectDiffSchema(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	d := Sqlite{}
+	b := sqlbuilder.NewBuildable(d)
+
+	if _, err = db.Exec(`CREATE TABLE "accounts" ( "id" INTEGER PRIMARY KEY, "name" TEXT, "email" TEXT )`); err != nil {
+		t.Fatal(err)
+	}
+	declared := []sqlbuilder.Table{
+		b.NewTable(
+			"accounts",
+			&sqlbuilder.TableOption{Unique: [][]string{{"name", "email"}}},
+			sqlbuilder.IntColumn("id", &sqlbuilder.ColumnOption{PrimaryKey: true}),
+			sqlbuilder.StringColumn("name", nil),
+			sqlbuilder.StringColumn("email", &sqlbuilder.ColumnOption{Unique: true}),
+			sqlbuilder.IntColumn("age", nil),
+			sqlbuilder.StringColumn("code", &sqlbuilder.ColumnOption{Unique: true}),
+		),
+		b.NewTable(
+			"tags",
+			nil,
+			sqlbuilder.IntColumn("id", &sqlbuilder.ColumnOption{PrimaryKey: true}),
+			sqlbuilder.StringColumn("label", &sqlbuilder.ColumnOption{NotNull: true}),
+		),
+	}
+
+	Convey("DiffSchema round trip", t, func() {
+		tables, err := Introspect(ctx, db, d)
+		So(err, ShouldBeNil)
+		diff, err := sqlbuilder.DiffSchema(b, tables, declared, nil)
+		So(err, ShouldBeNil)
+		So(diff.Empty(), ShouldBeFalse)
+		ex := sqlbuilder.NewExecutor(db, b)
+		for _, stmt := range diff.Statements {
+			_, err = ex.ExecContext(ctx, stmt)
+			So(err, ShouldBeNil)
+		}
+
+		tables, err = Introspect(ctx, db, d)
+		So(err, ShouldBeNil)
+		diff, err = sqlbuilder.DiffSchema(b, tables, declared, nil)
+		So(err, ShouldBeNil)
+		So(diff.Changes, ShouldBeEmpty)
+	})
+
+	Convey("DiffSchema column change", t, func() {
+		tables, err := Introspect(ctx, db, d)
+		So(err, ShouldBeNil)
+		changed := sqlbuilder.NewTable(
+			"tags",
+			nil,
+			sqlbuilder.IntColumn("id", &sqlbuilder.ColumnOption{PrimaryKey: true}),
+			sqlbuilder.IntColumn("label", &sqlbuilder.ColumnOption{NotNull: true}),
+		)
+		_, err = sqlbuilder.DiffSchema(b, tables, []sqlbuilder.Table{declared[0], changed}, nil)
+		So(err, ShouldNotBeNil)
+	})
+}
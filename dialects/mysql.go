@@ -22,8 +22,10 @@
 package dialects
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	sb "github.com/go-corelibs/go-sqlbuilder"
@@ -31,7 +33,12 @@ import (
 
 var _ sb.Dialect = MySql{}
 
-type MySql struct{}
+// MySql is the Dialect for MySQL and MariaDB servers
+type MySql struct {
+	// Version is the server version targeted, used to reject statements
+	// the server does not support. The zero value targets the latest release
+	Version string
+}
 
 func (m MySql) Name() string {
 	return "mysql"
@@ -75,6 +82,11 @@ func (m MySql) QuoteField(field interface{}) string {
 	return str
 }
 
+func (m MySql) QuoteString(value string) string {
+	// backslashes are escape characters unless NO_BACKSLASH_ESCAPES is set
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(value) + "'"
+}
+
 func (m MySql) ColumnTypeToString(cc sb.ColumnConfig) (string, error) {
 	if cc.Option().SqlType != "" {
 		return cc.Option().SqlType, nil
@@ -124,11 +136,178 @@ func (m MySql) ColumnOptionToString(co *sb.ColumnOption) (string, error) {
 
 func (m MySql) TableOptionToString(to *sb.TableOption) (string, error) {
 	opt := ""
+	if len(to.PrimaryKey) != 0 {
+		opt = tableOptionPrimaryKey(m, to.PrimaryKey)
+	}
 	if to.Unique != nil {
+		if len(opt) != 0 {
+			opt += ","
+		}
 		opt = str_append(opt, m.tableOptionUnique(to.Unique))
 	}
 
-	return opt, nil
+	return tableOptionForeignKeys(m, opt, to)
+}
+
+func (m MySql) SupportsCompound(operator string) bool {
+	switch operator {
+	case "INTERSECT", "EXCEPT":
+		return versionAtLeast(m.Version, "8.0.31")
+	}
+	return true
+}
+
+func (m MySql) MaxBindVars() int {
+	return 65535
+}
+
+func (m MySql) OnConflictToString(columns []string, doNothing bool) (string, error) {
+	if len(columns) == 0 {
+		return "", errors.New("dialects: conflict target columns are required")
+	}
+	if doNothing {
+		// assigning a column to itself leaves the existing row as-is
+		name := m.QuoteField(columns[0])
+		return "ON DUPLICATE KEY UPDATE " + name + "=" + name, nil
+	}
+	return "ON DUPLICATE KEY UPDATE", nil
+}
+
+func (m MySql) ExcludedColumnToString(column string) string {
+	return "VALUES(" + m.QuoteField(column) + ")"
+}
+
+func (m MySql) SupportsReturning() bool {
+	return false
+}
+
+func (m MySql) SupportsAnyAll() bool {
+	return true
+}
+
+func (m MySql) ConcatSyntax() (prefix, separator, suffix string) {
+	return "CONCAT(", ", ", ")"
+}
+
+// mysqlDateTruncFormats are the DATE_FORMAT formats truncating dates to the
+// date parts
+var mysqlDateTruncFormats = map[string]string{
+	"YEAR":   "%Y-01-01 00:00:00",
+	"MONTH":  "%Y-%m-01 00:00:00",
+	"DAY":    "%Y-%m-%d 00:00:00",
+	"HOUR":   "%Y-%m-%d %H:00:00",
+	"MINUTE": "%Y-%m-%d %H:%i:00",
+	"SECOND": "%Y-%m-%d %H:%i:%s",
+}
+
+func (m MySql) FuncToString(name string, args []string) (string, error) {
+	switch name {
+	case "LENGTH":
+		// LENGTH counts bytes
+		return "CHAR_LENGTH(" + strings.Join(args, ", ") + ")", nil
+	case "DATE_TRUNC":
+		if err := funcArgs(name, args, 2); err != nil {
+			return "", err
+		}
+		format, ok := mysqlDateTruncFormats[args[0]]
+		if !ok {
+			return "", errors.New("dialects: unknown date part " + args[0])
+		}
+		return "TIMESTAMP(DATE_FORMAT(" + args[1] + ", '" + format + "'))", nil
+	}
+	return funcToString(name, args)
+}
+
+func (m MySql) ForeignKeyToString(fk *sb.ForeignKey) (string, error) {
+	return foreignKeyToString(m, fk)
+}
+
+func (m MySql) AlterForeignKeyToString(fk *sb.ForeignKey, drop bool) (string, error) {
+	if drop {
+		if len(fk.Name) == 0 {
+			return "", errors.New("dialects: foreign key name is required")
+		}
+		return "DROP FOREIGN KEY " + m.QuoteField(fk.Name), nil
+	}
+	opt, err := foreignKeyToString(m, fk)
+	if err != nil {
+		return "", err
+	}
+	return "ADD " + opt, nil
+}
+
+func (m MySql) CheckToString(name, expression string) (string, error) {
+	return checkToString(m, name, expression)
+}
+
+func (m MySql) BeginToString(isolation sql.IsolationLevel, readOnly bool) ([]string, error) {
+	var queries []string
+	if isolation != sql.LevelDefault {
+		level, err := isolationLevelToString(isolation)
+		if err != nil {
+			return nil, err
+		}
+		// applies to the next transaction only
+		queries = append(queries, "SET TRANSACTION ISOLATION LEVEL "+level)
+	}
+	if readOnly {
+		if !versionAtLeast(m.Version, "5.6.5") {
+			return nil, errors.New("dialects: read-only transactions need mysql 5.6.5")
+		}
+		return append(queries, "START TRANSACTION READ ONLY"), nil
+	}
+	return append(queries, "START TRANSACTION"), nil
+}
+
+func (m MySql) SavepointToString(action sb.SavepointAction, name string) (string, error) {
+	return savepointToString(m, action, name)
+}
+
+func (m MySql) TableExistsToString() string {
+	return `SELECT COUNT(*) FROM information_schema.TABLES ` +
+		`WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ` + m.BindVar(1)
+}
+
+func (m MySql) AlterTableToString(table string, actions []sb.AlterTableAction) ([]string, error) {
+	position := func(action sb.AlterTableAction) string {
+		if action.First {
+			return " FIRST"
+		} else if len(action.After) != 0 {
+			return " AFTER " + m.QuoteField(action.After)
+		}
+		return ""
+	}
+
+	parts := make([]string, 0, len(actions))
+	for _, action := range actions {
+		switch action.Type {
+		case sb.AlterTableAddColumn:
+			def, err := columnDefinition(m, action.Column)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, "ADD COLUMN "+def+position(action))
+		case sb.AlterTableChangeColumn:
+			def, err := columnDefinition(m, action.Column)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, "CHANGE COLUMN "+m.QuoteField(action.OldColumn.Name())+" "+def+position(action))
+		case sb.AlterTableDropColumn:
+			parts = append(parts, "DROP COLUMN "+m.QuoteField(action.OldColumn.Name()))
+		case sb.AlterTableAddForeignKey, sb.AlterTableDropForeignKey:
+			opt, err := m.AlterForeignKeyToString(action.ForeignKey, action.Type == sb.AlterTableDropForeignKey)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, opt)
+		case sb.AlterTableRename:
+			parts = append(parts, "RENAME TO "+m.QuoteField(action.Name))
+		default:
+			return nil, errors.New("dialects: unknown alter table action")
+		}
+	}
+	return []string{"ALTER TABLE " + m.QuoteField(table) + " " + strings.Join(parts, ", ")}, nil
 }
 
 func (m MySql) tableOptionUnique(op [][]string) string {
@@ -22,6 +22,7 @@
 package dialects
 
 import (
+	"database/sql"
 	"fmt"
 	"testing"
 	"time"
@@ -44,6 +45,10 @@ func TestMysql(t *testing.T) {
 		So(d.BindVar(2), ShouldEqual, `?`)
 	})
 
+	Convey("MaxBindVars", t, func() {
+		So(d.MaxBindVars(), ShouldEqual, 65535)
+	})
+
 	Convey("QuoteField", t, func() {
 		now := time.Now()
 		for idx, test := range []struct {
@@ -66,6 +71,17 @@ func TestMysql(t *testing.T) {
 		}
 	})
 
+	Convey("QuoteString", t, func() {
+		So(d.QuoteString(`it's`), ShouldEqual, `'it''s'`)
+		So(d.QuoteString(`C:\temp\`), ShouldEqual, `'C:\\temp\\'`)
+
+		table := sqlbuilder.NewTable("paths", &sqlbuilder.TableOption{}, sqlbuilder.StringColumn("path", &sqlbuilder.ColumnOption{Size: 255}))
+		table.Option().Checks = []sqlbuilder.Check{{Condition: table.C("path").NotEq(`C:\`)}}
+		query, _, err := sqlbuilder.NewBuildable(d).CreateTable(table).ToSql()
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, "CREATE TABLE `paths` ( `path` VARCHAR(255), CHECK (`path`<>'C:\\\\') );")
+	})
+
 	Convey("ColumnTypeToString", t, func() {
 
 		for idx, test := range []struct {
@@ -197,6 +213,31 @@ func TestMysql(t *testing.T) {
 				"UNIQUE(`one`) UNIQUE(`two`)",
 				ShouldBeNil,
 			},
+			{
+				&sqlbuilder.TableOption{
+					Unique: [][]string{{"one"}},
+					ForeignKeys: []sqlbuilder.ForeignKey{
+						{Columns: []string{"one"}, RefTable: "other", RefColumns: []string{"id"}, OnUpdate: sqlbuilder.ForeignKeyCascade},
+					},
+				},
+				"UNIQUE(`one`), FOREIGN KEY (`one`) REFERENCES `other` (`id`) ON UPDATE CASCADE",
+				ShouldBeNil,
+			},
+			{
+				&sqlbuilder.TableOption{
+					PrimaryKey: []string{"one", "two"},
+					Unique:     [][]string{{"three"}},
+				},
+				"PRIMARY KEY (`one`, `two`), UNIQUE(`three`)",
+				ShouldBeNil,
+			},
+			{
+				&sqlbuilder.TableOption{
+					ForeignKeys: []sqlbuilder.ForeignKey{{Columns: []string{"one"}}},
+				},
+				``,
+				ShouldNotBeNil,
+			},
 		} {
 			Convey(fmt.Sprintf("case #%d", idx), func() {
 				str, err := d.TableOptionToString(test.input)
@@ -206,4 +247,137 @@ func TestMysql(t *testing.T) {
 		}
 
 	})
+
+	Convey("OnConflictToString", t, func() {
+		str, err := d.OnConflictToString([]string{"one", "two"}, true)
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "ON DUPLICATE KEY UPDATE `one`=`one`")
+		str, err = d.OnConflictToString([]string{"one", "two"}, false)
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "ON DUPLICATE KEY UPDATE")
+		_, err = d.OnConflictToString(nil, false)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("ExcludedColumnToString", t, func() {
+		So(d.ExcludedColumnToString("one"), ShouldEqual, "VALUES(`one`)")
+	})
+
+	Convey("SupportsReturning", t, func() {
+		So(d.SupportsReturning(), ShouldBeFalse)
+	})
+
+	Convey("SupportsAnyAll", t, func() {
+		So(d.SupportsAnyAll(), ShouldBeTrue)
+	})
+
+	Convey("ConcatSyntax", t, func() {
+		prefix, separator, suffix := d.ConcatSyntax()
+		So(prefix, ShouldEqual, "CONCAT(")
+		So(separator, ShouldEqual, ", ")
+		So(suffix, ShouldEqual, ")")
+	})
+
+	Convey("FuncToString", t, func() {
+		str, err := d.FuncToString("LENGTH", []string{"`name`"})
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "CHAR_LENGTH(`name`)")
+		str, err = d.FuncToString("NOW", nil)
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "NOW()")
+		str, err = d.FuncToString("DATE_TRUNC", []string{"MONTH", "`created`"})
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "TIMESTAMP(DATE_FORMAT(`created`, '%Y-%m-01 00:00:00'))")
+		str, err = d.FuncToString("EXTRACT", []string{"YEAR", "`created`"})
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "EXTRACT(YEAR FROM `created`)")
+		_, err = d.FuncToString("DATE_TRUNC", []string{"MONTH"})
+		So(err, ShouldNotBeNil)
+		_, err = d.FuncToString("EXTRACT", nil)
+		So(err, ShouldNotBeNil)
+		_, err = d.FuncToString("DATE_TRUNC", []string{"WEEK", "`created`"})
+		So(err, ShouldNotBeNil)
+		_, err = d.FuncToString("UNKNOWN", nil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("ForeignKeyToString", t, func() {
+		str, err := d.ForeignKeyToString(&sqlbuilder.ForeignKey{
+			Name:       "fk_pair",
+			Columns:    []string{"one", "two"},
+			RefTable:   "other",
+			RefColumns: []string{"a", "b"},
+			OnDelete:   sqlbuilder.ForeignKeySetNull,
+			OnUpdate:   sqlbuilder.ForeignKeyRestrict,
+		})
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "CONSTRAINT `fk_pair` FOREIGN KEY (`one`, `two`) REFERENCES `other` (`a`, `b`) ON DELETE SET NULL ON UPDATE RESTRICT")
+
+		_, err = d.ForeignKeyToString(&sqlbuilder.ForeignKey{
+			Columns:    []string{"one", "two"},
+			RefTable:   "other",
+			RefColumns: []string{"a"},
+		})
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("AlterForeignKeyToString", t, func() {
+		fk := &sqlbuilder.ForeignKey{
+			Name:       "fk_one",
+			Columns:    []string{"one"},
+			RefTable:   "other",
+			RefColumns: []string{"id"},
+			OnDelete:   sqlbuilder.ForeignKeyCascade,
+		}
+		str, err := d.AlterForeignKeyToString(fk, false)
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "ADD CONSTRAINT `fk_one` FOREIGN KEY (`one`) REFERENCES `other` (`id`) ON DELETE CASCADE")
+		str, err = d.AlterForeignKeyToString(fk, true)
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "DROP FOREIGN KEY `fk_one`")
+		_, err = d.AlterForeignKeyToString(&sqlbuilder.ForeignKey{}, true)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("CheckToString", t, func() {
+		str, err := d.CheckToString("ck_one", "`one`>0")
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "CONSTRAINT `ck_one` CHECK (`one`>0)")
+		str, err = d.CheckToString("", "`one`>0")
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "CHECK (`one`>0)")
+		_, err = d.CheckToString("ck_one", "")
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("BeginToString", t, func() {
+		queries, err := d.BeginToString(sql.LevelDefault, false)
+		So(err, ShouldBeNil)
+		So(queries, ShouldResemble, []string{"START TRANSACTION"})
+		queries, err = d.BeginToString(sql.LevelRepeatableRead, true)
+		So(err, ShouldBeNil)
+		So(queries, ShouldResemble, []string{"SET TRANSACTION ISOLATION LEVEL REPEATABLE READ", "START TRANSACTION READ ONLY"})
+		_, err = d.BeginToString(sql.LevelSnapshot, false)
+		So(err, ShouldNotBeNil)
+		_, err = MySql{Version: "5.5.62"}.BeginToString(sql.LevelDefault, true)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("SavepointToString", t, func() {
+		str, err := d.SavepointToString(sqlbuilder.SavepointCreate, "sp_1")
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "SAVEPOINT `sp_1`")
+		str, err = d.SavepointToString(sqlbuilder.SavepointRelease, "sp_1")
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "RELEASE SAVEPOINT `sp_1`")
+		str, err = d.SavepointToString(sqlbuilder.SavepointRollback, "sp_1")
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, "ROLLBACK TO SAVEPOINT `sp_1`")
+		_, err = d.SavepointToString(sqlbuilder.SavepointCreate, "")
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("TableExistsToString", t, func() {
+		So(d.TableExistsToString(), ShouldEqual, "SELECT COUNT(*) FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?")
+	})
 }
@@ -22,9 +22,11 @@
 package dialects
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	sb "github.com/go-corelibs/go-sqlbuilder"
@@ -82,6 +84,10 @@ func (m Postgresql) QuoteField(field interface{}) string {
 	return str
 }
 
+func (m Postgresql) QuoteString(value string) string {
+	return quoteString(value)
+}
+
 func (m Postgresql) ColumnTypeToString(cc sb.ColumnConfig) (string, error) {
 	if cc.Option().SqlType != "" {
 		return cc.Option().SqlType, nil
@@ -131,11 +137,7 @@ func (m Postgresql) ColumnOptionToString(co *sb.ColumnOption) (string, error) {
 			opt = str_append(opt, "DEFAULT NULL")
 		}
 	} else {
-		str, bracket := m.quoteField(co.Default)
-		if bracket {
-			str = "'" + str + "'"
-		}
-		opt = str_append(opt, "DEFAULT "+str)
+		opt = str_append(opt, "DEFAULT "+m.defaultValue(co.Default))
 	}
 
 	return opt, nil
@@ -143,11 +145,220 @@ func (m Postgresql) ColumnOptionToString(co *sb.ColumnOption) (string, error) {
 
 func (m Postgresql) TableOptionToString(to *sb.TableOption) (string, error) {
 	opt := ""
+	if len(to.PrimaryKey) != 0 {
+		opt = tableOptionPrimaryKey(m, to.PrimaryKey)
+	}
 	if to.Unique != nil {
+		if len(opt) != 0 {
+			opt += ","
+		}
 		opt = str_append(opt, m.tableOptionUnique(to.Unique))
 	}
 
-	return opt, nil
+	return tableOptionForeignKeys(m, opt, to)
+}
+
+func (m Postgresql) SupportsCompound(operator string) bool {
+	return true
+}
+
+func (m Postgresql) MaxBindVars() int {
+	return 65535
+}
+
+func (m Postgresql) OnConflictToString(columns []string, doNothing bool) (string, error) {
+	if len(columns) == 0 {
+		return "", errors.New("dialects: conflict target columns are required")
+	}
+	opt := "ON CONFLICT ("
+	for idx, col := range columns {
+		if idx > 0 {
+			opt += ", "
+		}
+		opt += m.QuoteField(col)
+	}
+	if doNothing {
+		return opt + ") DO NOTHING", nil
+	}
+	return opt + ") DO UPDATE SET", nil
+}
+
+func (m Postgresql) ExcludedColumnToString(column string) string {
+	return "excluded." + m.QuoteField(column)
+}
+
+func (m Postgresql) SupportsReturning() bool {
+	return true
+}
+
+func (m Postgresql) SupportsAnyAll() bool {
+	return true
+}
+
+func (m Postgresql) ConcatSyntax() (prefix, separator, suffix string) {
+	return "", " || ", ""
+}
+
+func (m Postgresql) FuncToString(name string, args []string) (string, error) {
+	switch name {
+	case "DATE_TRUNC":
+		if err := funcArgs(name, args, 2); err != nil {
+			return "", err
+		}
+		return "DATE_TRUNC('" + strings.ToLower(args[0]) + "', " + args[1] + ")", nil
+	case "ROUND":
+		// ROUND of a number of decimals is only defined for NUMERIC, not REAL
+		if len(args) == 2 {
+			return "ROUND(CAST(" + args[0] + " AS NUMERIC), " + args[1] + ")", nil
+		}
+	}
+	return funcToString(name, args)
+}
+
+func (m Postgresql) ForeignKeyToString(fk *sb.ForeignKey) (string, error) {
+	return foreignKeyToString(m, fk)
+}
+
+func (m Postgresql) AlterForeignKeyToString(fk *sb.ForeignKey, drop bool) (string, error) {
+	if drop {
+		if len(fk.Name) == 0 {
+			return "", errors.New("dialects: foreign key name is required")
+		}
+		return "DROP CONSTRAINT " + m.QuoteField(fk.Name), nil
+	}
+	opt, err := foreignKeyToString(m, fk)
+	if err != nil {
+		return "", err
+	}
+	return "ADD " + opt, nil
+}
+
+func (m Postgresql) BeginToString(isolation sql.IsolationLevel, readOnly bool) ([]string, error) {
+	query := "BEGIN"
+	if isolation != sql.LevelDefault {
+		level, err := isolationLevelToString(isolation)
+		if err != nil {
+			return nil, err
+		}
+		query += " ISOLATION LEVEL " + level
+	}
+	if readOnly {
+		if isolation != sql.LevelDefault {
+			query += ","
+		}
+		query += " READ ONLY"
+	}
+	return []string{query}, nil
+}
+
+func (m Postgresql) SavepointToString(action sb.SavepointAction, name string) (string, error) {
+	return savepointToString(m, action, name)
+}
+
+func (m Postgresql) TableExistsToString() string {
+	return `SELECT COUNT(*) FROM information_schema.tables ` +
+		`WHERE table_schema = current_schema() AND table_name = ` + m.BindVar(1)
+}
+
+func (m Postgresql) CheckToString(name, expression string) (string, error) {
+	return checkToString(m, name, expression)
+}
+
+func (m Postgresql) AlterTableToString(table string, actions []sb.AlterTableAction) ([]string, error) {
+	prefix := "ALTER TABLE " + m.QuoteField(table) + " "
+
+	// RENAME forms can not be combined with other actions
+	var renames, parts []string
+	var rename string
+	for _, action := range actions {
+		if action.First || len(action.After) != 0 {
+			return nil, errors.New("dialects: postgres can not position columns with FIRST or AFTER")
+		}
+		switch action.Type {
+		case sb.AlterTableAddColumn:
+			def, err := columnDefinition(m, action.Column)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, "ADD COLUMN "+def)
+		case sb.AlterTableChangeColumn:
+			name := m.QuoteField(action.Column.Name())
+			if action.Column.Name() != action.OldColumn.Name() {
+				renames = append(renames, prefix+"RENAME COLUMN "+m.QuoteField(action.OldColumn.Name())+" TO "+name)
+			}
+			alter, err := m.alterColumn(action.OldColumn, action.Column)
+			if err != nil {
+				return nil, err
+			}
+			for _, part := range alter {
+				parts = append(parts, "ALTER COLUMN "+name+" "+part)
+			}
+			if action.Column.Option().Unique && !action.OldColumn.Option().Unique {
+				parts = append(parts, "ADD UNIQUE ("+name+")")
+			}
+		case sb.AlterTableDropColumn:
+			parts = append(parts, "DROP COLUMN "+m.QuoteField(action.OldColumn.Name()))
+		case sb.AlterTableAddForeignKey, sb.AlterTableDropForeignKey:
+			opt, err := m.AlterForeignKeyToString(action.ForeignKey, action.Type == sb.AlterTableDropForeignKey)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, opt)
+		case sb.AlterTableRename:
+			rename = prefix + "RENAME TO " + m.QuoteField(action.Name)
+		default:
+			return nil, errors.New("dialects: unknown alter table action")
+		}
+	}
+
+	stmts := renames
+	if len(parts) != 0 {
+		stmts = append(stmts, prefix+strings.Join(parts, ", "))
+	}
+	if len(rename) != 0 {
+		stmts = append(stmts, rename)
+	}
+	return stmts, nil
+}
+
+// alterColumn returns the ALTER COLUMN actions changing the type, NOT NULL
+// and DEFAULT settings of a column to the new definition
+func (m Postgresql) alterColumn(old, cc sb.ColumnConfig) ([]string, error) {
+	opt, prev := cc.Option(), old.Option()
+	if opt.PrimaryKey != prev.PrimaryKey || opt.AutoIncrement != prev.AutoIncrement {
+		return nil, errors.New("dialects: postgres can not change the PRIMARY KEY or SERIAL settings of a column")
+	}
+	if prev.Unique && !opt.Unique {
+		return nil, errors.New("dialects: postgres can not drop an unnamed UNIQUE constraint")
+	}
+
+	typ, err := m.ColumnTypeToString(cc)
+	if err != nil {
+		return nil, err
+	} else if typ == "SERIAL" {
+		// SERIAL is only a shorthand within CREATE TABLE
+		typ = "INTEGER"
+	}
+	parts := []string{"TYPE " + typ}
+	if opt.NotNull {
+		parts = append(parts, "SET NOT NULL")
+	} else {
+		parts = append(parts, "DROP NOT NULL")
+	}
+	if opt.Default != nil {
+		parts = append(parts, "SET DEFAULT "+m.defaultValue(opt.Default))
+	} else if !opt.AutoIncrement {
+		parts = append(parts, "DROP DEFAULT")
+	}
+	return parts, nil
+}
+
+func (m Postgresql) defaultValue(value interface{}) string {
+	str, bracket := m.quoteField(value)
+	if bracket {
+		str = "'" + str + "'"
+	}
+	return str
 }
 
 func (m Postgresql) tableOptionUnique(op [][]string) string {
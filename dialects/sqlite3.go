@@ -22,6 +22,7 @@
 package dialects
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"strconv"
@@ -32,7 +33,12 @@ import (
 
 var _ sb.Dialect = Sqlite{}
 
-type Sqlite struct{}
+// Sqlite is the Dialect for SQLite3 databases
+type Sqlite struct {
+	// Version is the library version targeted, used to reject statements
+	// the library does not support. The zero value targets the latest release
+	Version string
+}
 
 func (m Sqlite) Name() string {
 	return "sqlite3"
@@ -69,6 +75,10 @@ func (m Sqlite) QuoteField(field interface{}) string {
 	return ""
 }
 
+func (m Sqlite) QuoteString(value string) string {
+	return quoteString(value)
+}
+
 func (m Sqlite) ColumnTypeToString(cc sb.ColumnConfig) (string, error) {
 	if cc.Option().SqlType != "" {
 		return cc.Option().SqlType, nil
@@ -119,11 +129,205 @@ func (m Sqlite) ColumnOptionToString(co *sb.ColumnOption) (string, error) {
 
 func (m Sqlite) TableOptionToString(to *sb.TableOption) (string, error) {
 	opt := ""
+	if len(to.PrimaryKey) != 0 {
+		opt = tableOptionPrimaryKey(m, to.PrimaryKey)
+	}
 	if to.Unique != nil {
+		if len(opt) != 0 {
+			opt += ","
+		}
 		opt = str_append(opt, m.tableOptionUnique(to.Unique))
 	}
 
-	return opt, nil
+	return tableOptionForeignKeys(m, opt, to)
+}
+
+func (m Sqlite) SupportsCompound(operator string) bool {
+	return true
+}
+
+func (m Sqlite) MaxBindVars() int {
+	if versionAtLeast(m.Version, "3.32.0") {
+		return 32766
+	}
+	return 999
+}
+
+func (m Sqlite) OnConflictToString(columns []string, doNothing bool) (string, error) {
+	if !versionAtLeast(m.Version, "3.24.0") {
+		return "", errors.New("dialects: ON CONFLICT requires sqlite 3.24.0 or later")
+	}
+	if len(columns) == 0 {
+		return "", errors.New("dialects: conflict target columns are required")
+	}
+	opt := "ON CONFLICT ("
+	for idx, col := range columns {
+		if idx > 0 {
+			opt += ", "
+		}
+		opt += m.QuoteField(col)
+	}
+	if doNothing {
+		return opt + ") DO NOTHING", nil
+	}
+	return opt + ") DO UPDATE SET", nil
+}
+
+func (m Sqlite) ExcludedColumnToString(column string) string {
+	return "excluded." + m.QuoteField(column)
+}
+
+func (m Sqlite) SupportsReturning() bool {
+	return versionAtLeast(m.Version, "3.35.0")
+}
+
+func (m Sqlite) SupportsAnyAll() bool {
+	return false
+}
+
+func (m Sqlite) ConcatSyntax() (prefix, separator, suffix string) {
+	return "", " || ", ""
+}
+
+// sqliteDateTruncFormats are the strftime formats truncating dates to the
+// date parts
+var sqliteDateTruncFormats = map[string]string{
+	"YEAR":   "%Y-01-01 00:00:00",
+	"MONTH":  "%Y-%m-01 00:00:00",
+	"DAY":    "%Y-%m-%d 00:00:00",
+	"HOUR":   "%Y-%m-%d %H:00:00",
+	"MINUTE": "%Y-%m-%d %H:%M:00",
+	"SECOND": "%Y-%m-%d %H:%M:%S",
+}
+
+// sqliteExtractFormats are the strftime formats extracting the date parts
+var sqliteExtractFormats = map[string]string{
+	"YEAR":   "%Y",
+	"MONTH":  "%m",
+	"DAY":    "%d",
+	"HOUR":   "%H",
+	"MINUTE": "%M",
+	"SECOND": "%S",
+}
+
+func (m Sqlite) FuncToString(name string, args []string) (string, error) {
+	switch name {
+	case "NOW":
+		return "datetime('now')", nil
+	case "DATE_TRUNC":
+		if err := funcArgs(name, args, 2); err != nil {
+			return "", err
+		}
+		format, ok := sqliteDateTruncFormats[args[0]]
+		if !ok {
+			return "", errors.New("dialects: unknown date part " + args[0])
+		}
+		return "strftime('" + format + "', " + args[1] + ")", nil
+	case "EXTRACT":
+		if err := funcArgs(name, args, 2); err != nil {
+			return "", err
+		}
+		format, ok := sqliteExtractFormats[args[0]]
+		if !ok {
+			return "", errors.New("dialects: unknown date part " + args[0])
+		}
+		return "CAST(strftime('" + format + "', " + args[1] + ") AS INTEGER)", nil
+	}
+	return funcToString(name, args)
+}
+
+func (m Sqlite) ForeignKeyToString(fk *sb.ForeignKey) (string, error) {
+	return foreignKeyToString(m, fk)
+}
+
+func (m Sqlite) AlterForeignKeyToString(fk *sb.ForeignKey, drop bool) (string, error) {
+	// sqlite only accepts foreign keys within CREATE TABLE statements
+	return "", errors.New("dialects: sqlite can not alter the foreign keys of an existing table")
+}
+
+func (m Sqlite) BeginToString(isolation sql.IsolationLevel, readOnly bool) ([]string, error) {
+	// sqlite transactions are always serializable
+	if isolation != sql.LevelDefault && isolation != sql.LevelSerializable {
+		return nil, errors.New("dialects: isolation level " + isolation.String() + " is not supported")
+	}
+	if readOnly {
+		return nil, errors.New("dialects: read-only transactions are not supported")
+	}
+	return []string{"BEGIN"}, nil
+}
+
+func (m Sqlite) SavepointToString(action sb.SavepointAction, name string) (string, error) {
+	return savepointToString(m, action, name)
+}
+
+func (m Sqlite) TableExistsToString() string {
+	return `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ` + m.BindVar(1)
+}
+
+func (m Sqlite) CheckToString(name, expression string) (string, error) {
+	return checkToString(m, name, expression)
+}
+
+func (m Sqlite) AlterTableToString(table string, actions []sb.AlterTableAction) ([]string, error) {
+	// sqlite only accepts one action per ALTER TABLE statement
+	stmts := make([]string, 0, len(actions))
+	for _, action := range actions {
+		if action.First || len(action.After) != 0 {
+			return nil, errors.New("dialects: sqlite can not position columns with FIRST or AFTER")
+		}
+		var stmt string
+		switch action.Type {
+		case sb.AlterTableAddColumn:
+			if opt := action.Column.Option(); opt.PrimaryKey || opt.Unique {
+				return nil, errors.New("dialects: sqlite can not add a PRIMARY KEY or UNIQUE column, rebuild the table instead")
+			}
+			def, err := columnDefinition(m, action.Column)
+			if err != nil {
+				return nil, err
+			}
+			stmt = "ADD COLUMN " + def
+		case sb.AlterTableChangeColumn:
+			if !m.sameDefinition(action.OldColumn, action.Column) {
+				return nil, errors.New("dialects: sqlite can only rename columns, rebuild the table instead")
+			}
+			if !versionAtLeast(m.Version, "3.25.0") {
+				return nil, errors.New("dialects: RENAME COLUMN requires sqlite 3.25.0 or later")
+			}
+			stmt = "RENAME COLUMN " + m.QuoteField(action.OldColumn.Name()) + " TO " + m.QuoteField(action.Column.Name())
+		case sb.AlterTableDropColumn:
+			if !versionAtLeast(m.Version, "3.35.0") {
+				return nil, errors.New("dialects: DROP COLUMN requires sqlite 3.35.0 or later")
+			}
+			stmt = "DROP COLUMN " + m.QuoteField(action.OldColumn.Name())
+		case sb.AlterTableAddForeignKey, sb.AlterTableDropForeignKey:
+			opt, err := m.AlterForeignKeyToString(action.ForeignKey, action.Type == sb.AlterTableDropForeignKey)
+			if err != nil {
+				return nil, err
+			}
+			stmt = opt
+		case sb.AlterTableRename:
+			stmt = "RENAME TO " + m.QuoteField(action.Name)
+		default:
+			return nil, errors.New("dialects: unknown alter table action")
+		}
+		stmts = append(stmts, "ALTER TABLE "+m.QuoteField(table)+" "+stmt)
+		if action.Type == sb.AlterTableRename {
+			table = action.Name
+		}
+	}
+	return stmts, nil
+}
+
+// sameDefinition reports whether the columns given differ only by name
+func (m Sqlite) sameDefinition(a, b sb.ColumnConfig) bool {
+	atyp, aerr := m.ColumnTypeToString(a)
+	btyp, berr := m.ColumnTypeToString(b)
+	aopt, aoerr := m.ColumnOptionToString(a.Option())
+	bopt, boerr := m.ColumnOptionToString(b.Option())
+	if aerr != nil || berr != nil || aoerr != nil || boerr != nil {
+		return false
+	}
+	return atyp == btyp && aopt == bopt
 }
 
 func (m Sqlite) tableOptionUnique(op [][]string) (opt string) {
@@ -22,6 +22,7 @@
 package dialects
 
 import (
+	"database/sql"
 	"fmt"
 	"testing"
 	"time"
@@ -44,6 +45,12 @@ func TestSqlite3(t *testing.T) {
 		So(d.BindVar(2), ShouldEqual, `?`)
 	})
 
+	Convey("MaxBindVars", t, func() {
+		So(d.MaxBindVars(), ShouldEqual, 32766)
+		So(Sqlite{Version: "3.32.0"}.MaxBindVars(), ShouldEqual, 32766)
+		So(Sqlite{Version: "3.31.1"}.MaxBindVars(), ShouldEqual, 999)
+	})
+
 	Convey("QuoteField", t, func() {
 		now := time.Now()
 		for idx, test := range []struct {
@@ -66,6 +73,11 @@ func TestSqlite3(t *testing.T) {
 		}
 	})
 
+	Convey("QuoteString", t, func() {
+		So(d.QuoteString(`it's`), ShouldEqual, `'it''s'`)
+		So(d.QuoteString(`C:\temp\`), ShouldEqual, `'C:\temp\'`)
+	})
+
 	Convey("ColumnTypeToString", t, func() {
 
 		for idx, test := range []struct {
@@ -197,6 +209,31 @@ func TestSqlite3(t *testing.T) {
 				`UNIQUE("one"), UNIQUE("two")`,
 				ShouldBeNil,
 			},
+			{
+				&sqlbuilder.TableOption{
+					Unique: [][]string{{"one"}},
+					ForeignKeys: []sqlbuilder.ForeignKey{
+						{Columns: []string{"one"}, RefTable: "other", RefColumns: []string{"id"}, OnUpdate: sqlbuilder.ForeignKeyCascade},
+					},
+				},
+				`UNIQUE("one"), FOREIGN KEY ("one") REFERENCES "other" ("id") ON UPDATE CASCADE`,
+				ShouldBeNil,
+			},
+			{
+				&sqlbuilder.TableOption{
+					PrimaryKey: []string{"one", "two"},
+					Unique:     [][]string{{"three"}},
+				},
+				`PRIMARY KEY ("one", "two"), UNIQUE("three")`,
+				ShouldBeNil,
+			},
+			{
+				&sqlbuilder.TableOption{
+					ForeignKeys: []sqlbuilder.ForeignKey{{Columns: []string{"one"}}},
+				},
+				``,
+				ShouldNotBeNil,
+			},
 		} {
 			Convey(fmt.Sprintf("case #%d", idx), func() {
 				str, err := d.TableOptionToString(test.input)
@@ -206,4 +243,136 @@ func TestSqlite3(t *testing.T) {
 		}
 
 	})
+
+	Convey("OnConflictToString", t, func() {
+		str, err := d.OnConflictToString([]string{"one", "two"}, true)
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, `ON CONFLICT ("one", "two") DO NOTHING`)
+		str, err = d.OnConflictToString([]string{"one", "two"}, false)
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, `ON CONFLICT ("one", "two") DO UPDATE SET`)
+		_, err = d.OnConflictToString(nil, false)
+		So(err, ShouldNotBeNil)
+		_, err = Sqlite{Version: "3.23.1"}.OnConflictToString([]string{"one"}, true)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("ExcludedColumnToString", t, func() {
+		So(d.ExcludedColumnToString("one"), ShouldEqual, `excluded."one"`)
+	})
+
+	Convey("SupportsReturning", t, func() {
+		So(d.SupportsReturning(), ShouldBeTrue)
+		So(Sqlite{Version: "3.35.0"}.SupportsReturning(), ShouldBeTrue)
+		So(Sqlite{Version: "3.34.1"}.SupportsReturning(), ShouldBeFalse)
+	})
+
+	Convey("SupportsAnyAll", t, func() {
+		So(d.SupportsAnyAll(), ShouldBeFalse)
+	})
+
+	Convey("ConcatSyntax", t, func() {
+		prefix, separator, suffix := d.ConcatSyntax()
+		So(prefix, ShouldEqual, "")
+		So(separator, ShouldEqual, " || ")
+		So(suffix, ShouldEqual, "")
+	})
+
+	Convey("FuncToString", t, func() {
+		str, err := d.FuncToString("LENGTH", []string{`"name"`})
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, `LENGTH("name")`)
+		str, err = d.FuncToString("NOW", nil)
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, `datetime('now')`)
+		str, err = d.FuncToString("DATE_TRUNC", []string{"HOUR", `"created"`})
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, `strftime('%Y-%m-%d %H:00:00', "created")`)
+		str, err = d.FuncToString("EXTRACT", []string{"MONTH", `"created"`})
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, `CAST(strftime('%m', "created") AS INTEGER)`)
+		_, err = d.FuncToString("EXTRACT", []string{"WEEK", `"created"`})
+		So(err, ShouldNotBeNil)
+		_, err = d.FuncToString("DATE_TRUNC", []string{"HOUR"})
+		So(err, ShouldNotBeNil)
+		_, err = d.FuncToString("EXTRACT", nil)
+		So(err, ShouldNotBeNil)
+		_, err = d.FuncToString("UNKNOWN", nil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("ForeignKeyToString", t, func() {
+		str, err := d.ForeignKeyToString(&sqlbuilder.ForeignKey{
+			Name:       "fk_pair",
+			Columns:    []string{"one", "two"},
+			RefTable:   "other",
+			RefColumns: []string{"a", "b"},
+			OnDelete:   sqlbuilder.ForeignKeySetNull,
+			OnUpdate:   sqlbuilder.ForeignKeyRestrict,
+		})
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, `CONSTRAINT "fk_pair" FOREIGN KEY ("one", "two") REFERENCES "other" ("a", "b") ON DELETE SET NULL ON UPDATE RESTRICT`)
+
+		_, err = d.ForeignKeyToString(&sqlbuilder.ForeignKey{
+			Columns:    []string{"one", "two"},
+			RefTable:   "other",
+			RefColumns: []string{"a"},
+		})
+		So(err, ShouldNotBeNil)
+
+		_, err = d.ForeignKeyToString(&sqlbuilder.ForeignKey{
+			Columns:  []string{"one"},
+			RefTable: "other",
+			OnDelete: sqlbuilder.ForeignKeyAction(9),
+		})
+		So(err, ShouldNotBeNil)
+		So(sqlbuilder.ForeignKeyAction(9).String(), ShouldEqual, "ForeignKeyAction(9)")
+	})
+
+	Convey("AlterForeignKeyToString", t, func() {
+		fk := &sqlbuilder.ForeignKey{Name: "fk_one", Columns: []string{"one"}, RefTable: "other"}
+		_, err := d.AlterForeignKeyToString(fk, false)
+		So(err, ShouldNotBeNil)
+		_, err = d.AlterForeignKeyToString(fk, true)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("CheckToString", t, func() {
+		str, err := d.CheckToString("ck_one", `"one">0`)
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, `CONSTRAINT "ck_one" CHECK ("one">0)`)
+		str, err = d.CheckToString("", `"one">0`)
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, `CHECK ("one">0)`)
+		_, err = d.CheckToString("ck_one", "")
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("BeginToString", t, func() {
+		queries, err := d.BeginToString(sql.LevelDefault, false)
+		So(err, ShouldBeNil)
+		So(queries, ShouldResemble, []string{"BEGIN"})
+		queries, err = d.BeginToString(sql.LevelSerializable, false)
+		So(err, ShouldBeNil)
+		So(queries, ShouldResemble, []string{"BEGIN"})
+		_, err = d.BeginToString(sql.LevelReadCommitted, false)
+		So(err, ShouldNotBeNil)
+		_, err = d.BeginToString(sql.LevelDefault, true)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("SavepointToString", t, func() {
+		str, err := d.SavepointToString(sqlbuilder.SavepointCreate, "sp_1")
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, `SAVEPOINT "sp_1"`)
+		str, err = d.SavepointToString(sqlbuilder.SavepointRelease, "sp_1")
+		So(err, ShouldBeNil)
+		So(str, ShouldEqual, `RELEASE SAVEPOINT "sp_1"`)
+		_, err = d.SavepointToString(sqlbuilder.SavepointRollback, "")
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("TableExistsToString", t, func() {
+		So(d.TableExistsToString(), ShouldEqual, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?")
+	})
 }
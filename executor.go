@@ -0,0 +1,263 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Querier is the part of *sql.DB, *sql.Tx and *sql.Conn used by Executor
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+var (
+	_ Querier = (*sql.DB)(nil)
+	_ Querier = (*sql.Tx)(nil)
+	_ Querier = (*sql.Conn)(nil)
+)
+
+// Executor is a Buildable which also runs the statements built, using a
+// *sql.DB, *sql.Tx or *sql.Conn. Errors returned by the database are wrapped
+// in a QueryError carrying the generated SQL.
+//
+// Executor is concurrency-safe when the Querier is
+type Executor interface {
+	Buildable
+
+	// Querier returns the database handle statements are run with
+	Querier() Querier
+
+	// Exec runs the statement without returning any rows. The statements of
+	// an AlterTableBuilder are run one at a time, returning the last result
+	Exec(stmt Statement) (sql.Result, error)
+	// ExecContext runs the statement without returning any rows. The
+	// statements of an AlterTableBuilder are run one at a time, returning the
+	// last result
+	ExecContext(ctx context.Context, stmt Statement) (sql.Result, error)
+
+	// Query runs the statement, returning the rows selected
+	Query(stmt Statement) (*sql.Rows, error)
+	// QueryContext runs the statement, returning the rows selected
+	QueryContext(ctx context.Context, stmt Statement) (*sql.Rows, error)
+
+	// QueryRow runs the statement, returning at most one row
+	QueryRow(stmt Statement) *Row
+	// QueryRowContext runs the statement, returning at most one row
+	QueryRowContext(ctx context.Context, stmt Statement) *Row
+
+	// ScanOne runs the SELECT statement, copying the first row into dest
+	// (see ScanRow) or returning sql.ErrNoRows, wrapped in a QueryError
+	ScanOne(stmt Statement, dest interface{}) error
+	// ScanOneContext runs the SELECT statement, copying the first row into
+	// dest (see ScanRow) or returning sql.ErrNoRows, wrapped in a QueryError
+	ScanOneContext(ctx context.Context, stmt Statement, dest interface{}) error
+
+	// ScanAll runs the SELECT statement, copying all rows into dest (see
+	// ScanRows)
+	ScanAll(stmt Statement, dest interface{}) error
+	// ScanAllContext runs the SELECT statement, copying all rows into dest
+	// (see ScanRows)
+	ScanAllContext(ctx context.Context, stmt Statement, dest interface{}) error
+}
+
+// QueryError is the error of running a statement, with the query and the
+// arguments given to the database
+type QueryError struct {
+	Query string
+	Args  []interface{}
+	Err   error
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("sqlbuilder: %v (query: %s)", e.Err, e.Query)
+}
+
+// Unwrap returns the error of the database
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// Row is the result of QueryRow. Like *sql.Row, any error is deferred until
+// Scan is called.
+type Row struct {
+	row   *sql.Row
+	query string
+	args  []interface{}
+	err   error
+}
+
+// Scan copies the columns of the row into the values pointed at by dest,
+// returning sql.ErrNoRows (wrapped in a QueryError) if there is no row
+func (r *Row) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	if err := r.row.Scan(dest...); err != nil {
+		return &QueryError{Query: r.query, Args: r.args, Err: err}
+	}
+	return nil
+}
+
+// Err returns the error of building or running the statement, without
+// scanning the row
+func (r *Row) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	if err := r.row.Err(); err != nil {
+		return &QueryError{Query: r.query, Args: r.args, Err: err}
+	}
+	return nil
+}
+
+type executor struct {
+	Buildable
+	db Querier
+}
+
+// NewExecutor constructs a new Executor running statements with the db given
+// and building them with the Buildable given
+func NewExecutor(db Querier, b Buildable) Executor {
+	return &executor{
+		Buildable: b,
+		db:        db,
+	}
+}
+
+func (e *executor) Querier() Querier {
+	return e.db
+}
+
+func (e *executor) Exec(stmt Statement) (sql.Result, error) {
+	return e.ExecContext(context.Background(), stmt)
+}
+
+func (e *executor) ExecContext(ctx context.Context, stmt Statement) (result sql.Result, err error) {
+	statements, err := splitStatement(stmt)
+	if err != nil {
+		return nil, err
+	}
+	for _, stmt := range statements {
+		query, args, err := stmt.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		if result, err = e.db.ExecContext(ctx, query, args...); err != nil {
+			return nil, &QueryError{Query: query, Args: args, Err: err}
+		}
+	}
+	return result, nil
+}
+
+// splitStatement returns the statements run for the statement given, which
+// are the statements of the dialect for an AlterTableBuilder
+func splitStatement(stmt Statement) ([]Statement, error) {
+	if alter, ok := stmt.(AlterTableBuilder); ok {
+		return alter.Statements()
+	}
+	return []Statement{stmt}, nil
+}
+
+func (e *executor) Query(stmt Statement) (*sql.Rows, error) {
+	return e.QueryContext(context.Background(), stmt)
+}
+
+func (e *executor) QueryContext(ctx context.Context, stmt Statement) (*sql.Rows, error) {
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := e.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, &QueryError{Query: query, Args: args, Err: err}
+	}
+	return rows, nil
+}
+
+func (e *executor) QueryRow(stmt Statement) *Row {
+	return e.QueryRowContext(context.Background(), stmt)
+}
+
+func (e *executor) QueryRowContext(ctx context.Context, stmt Statement) *Row {
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return &Row{err: err}
+	}
+	return &Row{
+		row:   e.db.QueryRowContext(ctx, query, args...),
+		query: query,
+		args:  args,
+	}
+}
+
+func (e *executor) ScanOne(stmt Statement, dest interface{}) error {
+	return e.ScanOneContext(context.Background(), stmt, dest)
+}
+
+func (e *executor) ScanOneContext(ctx context.Context, stmt Statement, dest interface{}) error {
+	if err := checkResultStatement(stmt); err != nil {
+		return err
+	}
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	rows, err := e.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return &QueryError{Query: query, Args: args, Err: err}
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err = rows.Err(); err == nil {
+			err = sql.ErrNoRows
+		}
+		return &QueryError{Query: query, Args: args, Err: err}
+	}
+	if err = ScanRow(rows, stmt, dest); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+func (e *executor) ScanAll(stmt Statement, dest interface{}) error {
+	return e.ScanAllContext(context.Background(), stmt, dest)
+}
+
+func (e *executor) ScanAllContext(ctx context.Context, stmt Statement, dest interface{}) error {
+	if err := checkResultStatement(stmt); err != nil {
+		return err
+	}
+	query, args, err := stmt.ToSql()
+	if err != nil {
+		return err
+	}
+	rows, err := e.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return &QueryError{Query: query, Args: args, Err: err}
+	}
+	return ScanRows(rows, stmt, dest)
+}
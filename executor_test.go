@@ -0,0 +1,138 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExecutor(t *testing.T) {
+	tbl := NewTable(
+		"TABLE_A",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		StringColumn("thing", nil),
+	)
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ex := NewExecutor(db, NewBuildable(TestingDialect{}))
+
+	Convey("Exec", t, func() {
+		So(ex.Querier(), ShouldEqual, db)
+
+		_, err := ex.Exec(ex.CreateTable(tbl))
+		So(err, ShouldBeNil)
+
+		result, err := ex.Exec(ex.Insert(tbl).
+			Values(1, "one").
+			Values(2, "two").
+			Values(3, "three"))
+		So(err, ShouldBeNil)
+		affected, err := result.RowsAffected()
+		So(err, ShouldBeNil)
+		So(affected, ShouldEqual, 3)
+
+		result, err = ex.Exec(ex.Update(tbl).Set(tbl.C("thing"), "THREE").Where(tbl.C("id").Eq(3)))
+		So(err, ShouldBeNil)
+		affected, err = result.RowsAffected()
+		So(err, ShouldBeNil)
+		So(affected, ShouldEqual, 1)
+	})
+
+	Convey("Query", t, func() {
+		rows, err := ex.Query(ex.Select(tbl).Columns(tbl.C("thing")).Where(tbl.C("id").Gt(1)).OrderBy(false, tbl.C("id")))
+		So(err, ShouldBeNil)
+		var things []string
+		for rows.Next() {
+			var thing string
+			So(rows.Scan(&thing), ShouldBeNil)
+			things = append(things, thing)
+		}
+		So(rows.Err(), ShouldBeNil)
+		So(rows.Close(), ShouldBeNil)
+		So(things, ShouldEqual, []string{"two", "THREE"})
+	})
+
+	Convey("QueryRow", t, func() {
+		var thing string
+		row := ex.QueryRow(ex.Select(tbl).Columns(tbl.C("thing")).Where(tbl.C("id").Eq(1)))
+		So(row.Err(), ShouldBeNil)
+		So(row.Scan(&thing), ShouldBeNil)
+		So(thing, ShouldEqual, "one")
+
+		err := ex.QueryRow(ex.Select(tbl).Columns(tbl.C("thing")).Where(tbl.C("id").Eq(10))).Scan(&thing)
+		So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+
+		err = ex.QueryRow(ex.Select(tbl).Columns(tbl.C("invalid"))).Scan(&thing)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, `sqlbuilder: column not found in FROM: ""`)
+	})
+
+	Convey("Errors carry the query", t, func() {
+		_, err := ex.Exec(ex.CreateTable(tbl))
+		var qerr *QueryError
+		So(errors.As(err, &qerr), ShouldBeTrue)
+		So(qerr.Query, ShouldEqual, `CREATE TABLE "TABLE_A" ( "id" INTEGER PRIMARY KEY, "thing" TEXT );`)
+		So(err.Error(), ShouldEqual, `sqlbuilder: table "TABLE_A" already exists (query: CREATE TABLE "TABLE_A" ( "id" INTEGER PRIMARY KEY, "thing" TEXT );)`)
+
+		_, err = ex.Query(ex.Select(tbl).Where(tbl.C("invalid").Eq(1)))
+		So(errors.As(err, &qerr), ShouldBeFalse)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Context", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := ex.QueryContext(ctx, ex.Select(tbl))
+		So(errors.Is(err, context.Canceled), ShouldBeTrue)
+	})
+
+	Convey("Transactions and connections", t, func() {
+		tx, err := db.Begin()
+		So(err, ShouldBeNil)
+		txe := NewExecutor(tx, ex)
+		_, err = txe.Exec(txe.Delete(tbl).Where(tbl.C("id").Eq(1)))
+		So(err, ShouldBeNil)
+		So(tx.Rollback(), ShouldBeNil)
+
+		conn, err := db.Conn(context.Background())
+		So(err, ShouldBeNil)
+		defer conn.Close()
+		var count int
+		row := NewExecutor(conn, ex).QueryRow(ex.Select(tbl).Columns(Func("COUNT", tbl.C("id"))))
+		So(row.Scan(&count), ShouldBeNil)
+		So(count, ShouldEqual, 3)
+	})
+}
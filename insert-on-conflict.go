@@ -0,0 +1,184 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+type cInsertConflict struct {
+	columns []Column
+	nothing bool
+	set     []serializable
+}
+
+func (c *cInsertConflict) serialize(b *builder) {
+	if !c.nothing && len(c.set) == 0 {
+		b.SetError(newError("ON CONFLICT needs DoNothing or DoUpdateSet."))
+		return
+	}
+
+	names := make([]string, len(c.columns))
+	for idx, col := range c.columns {
+		names[idx] = col.column_name()
+	}
+	clause, err := b.dialect.OnConflictToString(names, c.nothing)
+	if err != nil {
+		b.SetError(err)
+		return
+	}
+	b.Append(" " + clause)
+
+	if !c.nothing {
+		b.Append(" ")
+		b.AppendItems(c.set, ", ")
+	}
+}
+
+// bindVars returns the number of bind variables needed by the DO UPDATE SET
+// values, counted by rendering them in the dialect
+func (c *cInsertConflict) bindVars(d Dialect) int {
+	if c == nil || c.nothing {
+		return 0
+	}
+	bldr := newBuilder(d)
+	bldr.AppendItems(c.set, ", ")
+	return len(bldr.args)
+}
+
+func (c *cInsertConflict) Describe() (output string) {
+	output = "ON CONFLICT (" + ColumnList(c.columns).Describe() + ")"
+	if c.nothing {
+		output += " DO NOTHING"
+	} else {
+		output += " DO UPDATE"
+	}
+	return
+}
+
+type cExcludedColumn struct {
+	column Column
+}
+
+// Excluded returns a reference to the value of the column given, as proposed
+// for insertion by an INSERT statement with an ON CONFLICT clause. The Dialect
+// renders this as "excluded.column" or "VALUES(column)" accordingly.
+func Excluded(column Column) Column {
+	if IsColumnError(column) {
+		return column
+	}
+	return &cExcludedColumn{
+		column: column,
+	}
+}
+
+func (c *cExcludedColumn) table_name() string {
+	return c.column.table_name()
+}
+
+func (c *cExcludedColumn) column_name() string {
+	return c.column.column_name()
+}
+
+func (c *cExcludedColumn) config() ColumnConfig {
+	return c.column.config()
+}
+
+func (c *cExcludedColumn) acceptType(val interface{}) bool {
+	return c.column.acceptType(val)
+}
+
+func (c *cExcludedColumn) serialize(b *builder) {
+	b.Append(b.dialect.ExcludedColumnToString(c.column.column_name()))
+}
+
+func (c *cExcludedColumn) As(alias string) Column {
+	return &cColumnAlias{
+		column: c,
+		alias:  alias,
+	}
+}
+
+func (c *cExcludedColumn) Eq(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, "=")
+}
+
+func (c *cExcludedColumn) NotEq(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, "<>")
+}
+
+func (c *cExcludedColumn) Gt(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, ">")
+}
+
+func (c *cExcludedColumn) GtEq(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, ">=")
+}
+
+func (c *cExcludedColumn) Lt(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, "<")
+}
+
+func (c *cExcludedColumn) LtEq(right interface{}) Condition {
+	return newBinaryOperationCondition(c, right, "<=")
+}
+
+func (c *cExcludedColumn) Like(right string) Condition {
+	return newBinaryOperationCondition(c, right, " LIKE ")
+}
+
+func (c *cExcludedColumn) NotLike(right string) Condition {
+	return newBinaryOperationCondition(c, right, " NOT LIKE ")
+}
+
+func (c *cExcludedColumn) Between(lower, higher interface{}) Condition {
+	return newBetweenCondition(c, lower, higher)
+}
+
+func (c *cExcludedColumn) In(val ...interface{}) Condition {
+	return newInCondition(false, c, val...)
+}
+
+func (c *cExcludedColumn) NotIn(val ...interface{}) Condition {
+	return newInCondition(true, c, val...)
+}
+
+func (c *cExcludedColumn) Add(right interface{}) Column {
+	return newExpression("+", c, right)
+}
+
+func (c *cExcludedColumn) Sub(right interface{}) Column {
+	return newExpression("-", c, right)
+}
+
+func (c *cExcludedColumn) Mul(right interface{}) Column {
+	return newExpression("*", c, right)
+}
+
+func (c *cExcludedColumn) Div(right interface{}) Column {
+	return newExpression("/", c, right)
+}
+
+func (c *cExcludedColumn) Mod(right interface{}) Column {
+	return newExpression("%", c, right)
+}
+
+func (c *cExcludedColumn) Describe() (output string) {
+	output = "excluded." + c.column.column_name()
+	return
+}
@@ -25,17 +25,48 @@ package sqlbuilder
 type InsertBuilder interface {
 	Columns(columns ...Column) InsertBuilder
 	Values(values ...interface{}) InsertBuilder
+	Rows(rows [][]interface{}) InsertBuilder
 	Set(column Column, value interface{}) InsertBuilder
+
+	// FromStruct sets the columns of the table to the fields of the struct
+	// given (see NewTableFromStruct)
+	FromStruct(structure interface{}) InsertBuilder
+
+	// FromSelect uses the rows returned by the SELECT statement given instead
+	// of a VALUES clause
+	FromSelect(q SelectBuilder) InsertBuilder
+
+	// OnConflict starts an upsert clause for when the columns given, which
+	// must be a unique key of the table, conflict with an existing row
+	OnConflict(columns ...Column) InsertBuilder
+	// DoNothing skips inserting rows which conflict
+	DoNothing() InsertBuilder
+	// DoUpdateSet updates the column of a conflicting row with the value,
+	// use Excluded to reference the value proposed for insertion
+	DoUpdateSet(column Column, value interface{}) InsertBuilder
+
+	// Returning sets the columns of the inserted rows to return, all columns
+	// are returned when none are given
+	Returning(columns ...Column) InsertBuilder
+
 	ToSql() (query string, args []interface{}, err error)
 
+	// Batches splits the rows of this statement into as many INSERT
+	// statements as necessary to keep each one within the bind variable
+	// limit of the Dialect
+	Batches() []InsertBuilder
+
 	privateInsert()
 }
 
 // cInsert represents a INSERT statement.
 type cInsert struct {
-	columns ColumnList
-	values  []literal
-	into    Table
+	columns   ColumnList
+	values    [][]literal
+	source    *cSelect
+	into      Table
+	conflict  *cInsertConflict
+	returning cSelectColumnList
 
 	err error
 
@@ -64,7 +95,7 @@ func insert(into Table, d Dialect) *cInsert {
 	return &cInsert{
 		into:    into,
 		columns: make(ColumnList, 0),
-		values:  make([]literal, 0),
+		values:  make([][]literal, 0),
 		dialect: d,
 	}
 }
@@ -89,7 +120,8 @@ func (b *cInsert) Columns(columns ...Column) InsertBuilder {
 	return b
 }
 
-// Values sets VALUES clause. This overwrites old results of Values() or Set().
+// Values appends a row to the VALUES clause. Call many times to insert
+// multiple rows with one statement.
 func (b *cInsert) Values(values ...interface{}) InsertBuilder {
 	if b.err != nil {
 		return b
@@ -98,7 +130,15 @@ func (b *cInsert) Values(values ...interface{}) InsertBuilder {
 	for i := range values {
 		sl[i] = toLiteral(values[i])
 	}
-	b.values = sl
+	b.values = append(b.values, sl)
+	return b
+}
+
+// Rows appends all the rows given to the VALUES clause.
+func (b *cInsert) Rows(rows [][]interface{}) InsertBuilder {
+	for _, row := range rows {
+		b.Values(row...)
+	}
 	return b
 }
 
@@ -112,11 +152,162 @@ func (b *cInsert) Set(column Column, value interface{}) InsertBuilder {
 		b.err = newError("column not found in FROM.")
 		return b
 	}
+	if len(b.values) > 1 {
+		b.err = newError("Set can not be used with multiple rows.")
+		return b
+	}
+	if len(b.values) == 0 {
+		b.values = append(b.values, make([]literal, 0))
+	}
 	b.columns = append(b.columns, column)
-	b.values = append(b.values, toLiteral(value))
+	b.values[0] = append(b.values[0], toLiteral(value))
 	return b
 }
 
+// FromStruct calls Set for each column of the table matching a field of the
+// struct given, by the column names of NewTableFromStruct. Auto-increment
+// primary key columns and zero omitempty fields are skipped.
+func (b *cInsert) FromStruct(structure interface{}) InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	columns, values, err := structFieldValues(b.into.(*cTable), structure, func(col Column) bool {
+		opt := col.config().Option()
+		return opt.AutoIncrement && opt.PrimaryKey
+	})
+	if err != nil {
+		b.err = err
+		return b
+	}
+	for i := range columns {
+		b.Set(columns[i], values[i])
+	}
+	return b
+}
+
+// FromSelect sets the SELECT statement providing the rows to insert, the
+// number of columns selected must match the number of columns inserted.
+// FromSelect cannot be called with Values() or Set() in a statement.
+func (b *cInsert) FromSelect(q SelectBuilder) InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	s, ok := q.(*cSelect)
+	if !ok || s == nil {
+		b.err = newError("select statement is nil.")
+		return b
+	}
+	b.source = s
+	return b
+}
+
+// OnConflict sets "ON CONFLICT" clause (or the Dialect's equivalent). The
+// columns must be declared as a unique key by the TableOption.Unique or the
+// ColumnOption.Unique/PrimaryKey settings.
+func (b *cInsert) OnConflict(columns ...Column) InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	for _, col := range columns {
+		if !b.into.hasColumn(col) {
+			b.err = newError("column not found in table.")
+			return b
+		}
+	}
+	if !b.into.(*cTable).isUniqueKey(columns) {
+		b.err = newError("ON CONFLICT columns are not a unique key: %s", ColumnList(columns).Describe())
+		return b
+	}
+	b.conflict = &cInsertConflict{
+		columns: columns,
+	}
+	return b
+}
+
+// DoNothing sets "DO NOTHING" action of the ON CONFLICT clause.
+func (b *cInsert) DoNothing() InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	if b.conflict == nil {
+		b.err = newError("DoNothing needs OnConflict.")
+		return b
+	}
+	if len(b.conflict.set) > 0 {
+		b.err = newError("DoNothing can not be used with DoUpdateSet.")
+		return b
+	}
+	b.conflict.nothing = true
+	return b
+}
+
+// DoUpdateSet adds a column=value pair to the "DO UPDATE SET" action of the ON
+// CONFLICT clause.  Call many times to update multiple columns.
+func (b *cInsert) DoUpdateSet(column Column, value interface{}) InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	if b.conflict == nil {
+		b.err = newError("DoUpdateSet needs OnConflict.")
+		return b
+	}
+	if b.conflict.nothing {
+		b.err = newError("DoUpdateSet can not be used with DoNothing.")
+		return b
+	}
+	if !b.into.hasColumn(column) {
+		b.err = newError("column not found in table.")
+		return b
+	}
+	if err := checkUpdateValue(b.into, value, true); err != nil {
+		b.err = err
+		return b
+	}
+	b.conflict.set = append(b.conflict.set, newUpdateValue(column, value))
+	return b
+}
+
+// Returning sets RETURNING clause. Returns all columns (use *) if none are
+// given.
+func (b *cInsert) Returning(columns ...Column) InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.returning, b.err = checkReturning(b.into, columns)
+	return b
+}
+
+// Batches splits the rows into multiple INSERT statements when the number of
+// bind variables needed would exceed the Dialect's MaxBindVars limit. Returns
+// a list of just this statement when no splitting is needed.
+func (b *cInsert) Batches() []InsertBuilder {
+	if b.err != nil || len(b.values) <= 1 {
+		return []InsertBuilder{b}
+	}
+	width := len(b.columns)
+	if width == 0 {
+		width = len(b.into.Columns())
+	}
+	limit := b.dialect.MaxBindVars() - b.conflict.bindVars(b.dialect)
+	if limit <= 0 || width == 0 || width*len(b.values) <= limit {
+		return []InsertBuilder{b}
+	}
+
+	size := limit / width
+	if size < 1 {
+		size = 1
+	}
+	batches := make([]InsertBuilder, 0, len(b.values)/size+1)
+	for start := 0; start < len(b.values); start += size {
+		end := min(start+size, len(b.values))
+		batch := *b
+		// capped, so that Values on a batch does not overwrite the next one
+		batch.values = b.values[start:end:end]
+		batches = append(batches, &batch)
+	}
+	return batches
+}
+
 // ToSql generates query string, placeholder arguments, and returns err on errors.
 func (b *cInsert) ToSql() (query string, args []interface{}, err error) {
 	bldr := newBuilder(b.dialect)
@@ -143,26 +334,68 @@ func (b *cInsert) ToSql() (query string, args []interface{}, err error) {
 	bldr.AppendItem(b.columns)
 	bldr.Append(" )")
 
-	// VALUES
-	if len(b.columns) != len(b.values) {
-		bldr.SetError(newError("%d values needed, but got %d.", len(b.columns), len(b.values)))
-		return
-	}
-	for i := range b.columns {
-		if !b.columns[i].acceptType(b.values[i]) {
-			bldr.SetError(newError("%s column not accept %T.",
-				b.columns[i].config().Type().String(),
-				b.values[i].Raw()))
+	if b.source != nil {
+		// SELECT
+		if len(b.values) != 0 {
+			bldr.SetError(newError("FromSelect can not be used with Values or Set."))
 			return
 		}
+		if n := len(b.source.resultColumns()); n != len(b.columns) {
+			bldr.SetError(newError("%d columns needed, but select returns %d.", len(b.columns), n))
+			return
+		}
+		bldr.Append(" ")
+		bldr.AppendItem(b.source)
+	} else if !b.serializeValues(bldr) {
+		return
 	}
-	bldr.Append(" VALUES ( ")
-	values := make([]serializable, len(b.values))
-	for i := range values {
-		values[i] = b.values[i]
+
+	// ON CONFLICT
+	if b.conflict != nil {
+		bldr.AppendItem(b.conflict)
 	}
-	bldr.AppendItems(values, ", ")
-	bldr.Append(" )")
+
+	// RETURNING
+	serializeReturning(bldr, b.returning)
 
 	return
 }
+
+// serializeValues writes the VALUES clause, returning false on errors
+func (b *cInsert) serializeValues(bldr *builder) (ok bool) {
+	if len(b.values) == 0 {
+		bldr.SetError(newError("%d values needed, but got %d.", len(b.columns), 0))
+		return
+	}
+	if limit, needed := b.dialect.MaxBindVars(), len(b.columns)*len(b.values)+b.conflict.bindVars(b.dialect); limit > 0 && needed > limit {
+		bldr.SetError(newError("%d bind variables needed, but %s allows %d. (see InsertBuilder.Batches)",
+			needed, b.dialect.Name(), limit))
+		return
+	}
+	bldr.Append(" VALUES ")
+	for idx, row := range b.values {
+		if len(b.columns) != len(row) {
+			bldr.SetError(newError("%d values needed, but got %d.", len(b.columns), len(row)))
+			return
+		}
+		for i := range b.columns {
+			if !b.columns[i].acceptType(row[i]) {
+				bldr.SetError(newError("%s column not accept %T.",
+					b.columns[i].config().Type().String(),
+					row[i].Raw()))
+				return
+			}
+		}
+		if idx > 0 {
+			bldr.Append(", ")
+		}
+		bldr.Append("( ")
+		values := make([]serializable, len(row))
+		for i := range values {
+			values[i] = row[i]
+		}
+		bldr.AppendItems(values, ", ")
+		bldr.Append(" )")
+	}
+	return true
+}
@@ -72,6 +72,71 @@ func TestInsert(t *testing.T) {
 		query:  `INSERT INTO "TABLE_A" ( "id", "str", "bool", "float", "date", "bytes" ) VALUES ( ?, ?, ?, ?, ?, ? );`,
 		args:   []interface{}{int64(1), "hoge", true, 0.1, time.Unix(0, 0).UTC(), []byte{0x01}},
 		errmsg: "",
+	}, {
+		stmt: Insert(table1).
+			Columns(table1.C("id"), table1.C("str")).
+			Values(1, "hoge").
+			Values(2, "fuga"),
+		query:  `INSERT INTO "TABLE_A" ( "id", "str" ) VALUES ( ?, ? ), ( ?, ? );`,
+		args:   []interface{}{int64(1), "hoge", int64(2), "fuga"},
+		errmsg: "",
+	}, {
+		stmt: Insert(table1).
+			Columns(table1.C("id"), table1.C("str")).
+			Rows([][]interface{}{{1, "hoge"}, {2, "fuga"}, {3, nil}}),
+		query:  `INSERT INTO "TABLE_A" ( "id", "str" ) VALUES ( ?, ? ), ( ?, ? ), ( ?, ? );`,
+		args:   []interface{}{int64(1), "hoge", int64(2), "fuga", int64(3), nil},
+		errmsg: "",
+	}, {
+		stmt: Insert(table1).
+			Columns(table1.C("id"), table1.C("str")).
+			Rows([][]interface{}{{1, "hoge"}, {2, 3}}),
+		query:  "",
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: string column not accept int.",
+	}, {
+		stmt: Insert(table1).
+			Columns(table1.C("id"), table1.C("str")).
+			Rows([][]interface{}{{1, "hoge"}, {2}}),
+		query:  "",
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: 2 values needed, but got 1.",
+	}, {
+		stmt: Insert(table1).
+			Columns(table1.C("id")).
+			Values(1).
+			Values(2).
+			Set(table1.C("str"), "hoge"),
+		query:  "",
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: Set can not be used with multiple rows.",
+	}, {
+		stmt: Insert(table1).
+			Set(table1.C("str"), "hoge").
+			Returning(table1.C("id")),
+		query:  `INSERT INTO "TABLE_A" ( "str" ) VALUES ( ? ) RETURNING "TABLE_A"."id";`,
+		args:   []interface{}{"hoge"},
+		errmsg: "",
+	}, {
+		stmt: Insert(table2).
+			Columns(table2.C("id")).
+			FromSelect(Select(table1).Columns(table1.C("id")).Where(table1.C("str").Eq("hoge"))),
+		query:  `INSERT INTO "TABLE_B" ( "id" ) SELECT "TABLE_A"."id" FROM "TABLE_A" WHERE "TABLE_A"."str"=?;`,
+		args:   []interface{}{"hoge"},
+		errmsg: "",
+	}, {
+		stmt: Insert(table2).
+			FromSelect(Select(table1).Columns(table1.C("id"), table1.C("str"))),
+		query:  "",
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: 1 columns needed, but select returns 2.",
+	}, {
+		stmt: Insert(table2).
+			Set(table2.C("id"), 1).
+			FromSelect(Select(table1).Columns(table1.C("id"))),
+		query:  "",
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: FromSelect can not be used with Values or Set.",
 	}, {
 		stmt:   Insert(table1).Columns(table1.C("id")).Values(1, 2, 3),
 		query:  "",
@@ -101,3 +166,199 @@ func TestInsert(t *testing.T) {
 		}
 	}
 }
+
+func TestInsertOnConflict(t *testing.T) {
+	table1 := NewTable(
+		"TABLE_A",
+		&TableOption{
+			Unique: [][]string{{"name", "kind"}},
+		},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		StringColumn("name", nil),
+		StringColumn("kind", nil),
+		StringColumn("code", &ColumnOption{
+			Unique: true,
+		}),
+		IntColumn("count", nil),
+	)
+	table2 := NewTable(
+		"TABLE_B",
+		nil,
+		IntColumn("count", nil),
+	)
+
+	var cases = []statementTestCase{{
+		stmt: Insert(table1).
+			Values(1, "a", "b", "c", 2).
+			OnConflict(table1.C("id")).
+			DoNothing(),
+		query:  `INSERT INTO "TABLE_A" ( "id", "name", "kind", "code", "count" ) VALUES ( ?, ?, ?, ?, ? ) ON CONFLICT ("id") DO NOTHING;`,
+		args:   []interface{}{int64(1), "a", "b", "c", int64(2)},
+		errmsg: "",
+	}, {
+		stmt: Insert(table1).
+			Set(table1.C("name"), "a").
+			Set(table1.C("kind"), "b").
+			Set(table1.C("count"), 1).
+			OnConflict(table1.C("kind"), table1.C("name")).
+			DoUpdateSet(table1.C("count"), Excluded(table1.C("count"))).
+			DoUpdateSet(table1.C("code"), "z"),
+		query: `INSERT INTO "TABLE_A" ( "name", "kind", "count" ) VALUES ( ?, ?, ? ) ` +
+			`ON CONFLICT ("kind", "name") DO UPDATE SET "count"=excluded."count", "code"=?;`,
+		args:   []interface{}{"a", "b", int64(1), "z"},
+		errmsg: "",
+	}, {
+		stmt: Insert(table1).
+			Set(table1.C("code"), "c").
+			OnConflict(table1.C("code")).
+			DoUpdateSet(table1.C("count"), 1),
+		query:  `INSERT INTO "TABLE_A" ( "code" ) VALUES ( ? ) ON CONFLICT ("code") DO UPDATE SET "count"=?;`,
+		args:   []interface{}{"c", int64(1)},
+		errmsg: "",
+	}, {
+		stmt: Insert(table1).
+			Set(table1.C("name"), "a").
+			OnConflict(table1.C("name")).
+			DoNothing(),
+		query:  "",
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: ON CONFLICT columns are not a unique key: name",
+	}, {
+		stmt: Insert(table1).
+			Set(table1.C("name"), "a").
+			OnConflict(table1.C("id")),
+		query:  "",
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: ON CONFLICT needs DoNothing or DoUpdateSet.",
+	}, {
+		stmt: Insert(table1).
+			Set(table1.C("name"), "a").
+			DoNothing(),
+		query:  "",
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: DoNothing needs OnConflict.",
+	}, {
+		stmt: Insert(table1).
+			Set(table1.C("name"), "a").
+			OnConflict(table1.C("id")).
+			DoUpdateSet(table1.C("count"), "many"),
+		query:  "",
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: int column not accept string.",
+	}, {
+		stmt: Insert(table1).
+			Set(table1.C("code"), "c").
+			Set(table1.C("count"), 1).
+			OnConflict(table1.C("code")).
+			DoUpdateSet(table1.C("count"), table1.C("count").Add(Excluded(table1.C("count")))),
+		query:  `INSERT INTO "TABLE_A" ( "code", "count" ) VALUES ( ?, ? ) ON CONFLICT ("code") DO UPDATE SET "count"="TABLE_A"."count"+excluded."count";`,
+		args:   []interface{}{"c", int64(1)},
+		errmsg: "",
+	}, {
+		stmt: Insert(table1).
+			Set(table1.C("code"), "c").
+			OnConflict(table1.C("code")).
+			DoUpdateSet(table1.C("count"), table2.C("count")),
+		query:  "",
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: column TABLE_B.count was not found.",
+	}, {
+		stmt: Insert(table1).
+			Set(table1.C("code"), "c").
+			OnConflict(table1.C("code")).
+			DoUpdateSet(table1.C("count"), Excluded(table2.C("count")).Add(1)),
+		query:  "",
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: excluded column not found in table.",
+	}}
+
+	for num, c := range cases {
+		mes, args, ok := c.Run()
+		if !ok {
+			t.Errorf(mes+" (case no.%d)", append(args, num)...)
+		}
+	}
+}
+
+type limitedDialect struct {
+	TestingDialect
+}
+
+func (ld limitedDialect) MaxBindVars() int {
+	return 5
+}
+
+func TestInsertBatches(t *testing.T) {
+	table1 := NewTable(
+		"TABLE_A",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		StringColumn("str", nil),
+	)
+
+	stmt := insert(table1, limitedDialect{}).
+		Columns(table1.C("id"), table1.C("str")).
+		Rows([][]interface{}{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}, {5, "e"}})
+
+	if _, _, err := stmt.ToSql(); err == nil || err.Error() != "sqlbuilder: 10 bind variables needed, but testing allows 5. (see InsertBuilder.Batches)" {
+		t.Errorf("failed \ngot %v", err)
+	}
+
+	var cases = []statementTestCase{{
+		query:  `INSERT INTO "TABLE_A" ( "id", "str" ) VALUES ( ?, ? ), ( ?, ? );`,
+		args:   []interface{}{int64(1), "a", int64(2), "b"},
+		errmsg: "",
+	}, {
+		query:  `INSERT INTO "TABLE_A" ( "id", "str" ) VALUES ( ?, ? ), ( ?, ? );`,
+		args:   []interface{}{int64(3), "c", int64(4), "d"},
+		errmsg: "",
+	}, {
+		query:  `INSERT INTO "TABLE_A" ( "id", "str" ) VALUES ( ?, ? );`,
+		args:   []interface{}{int64(5), "e"},
+		errmsg: "",
+	}}
+
+	batches := stmt.Batches()
+	if len(batches) != len(cases) {
+		t.Fatalf("failed \nexpected %d batches, got %d", len(cases), len(batches))
+	}
+	for num, c := range cases {
+		c.stmt = batches[num]
+		mes, args, ok := c.Run()
+		if !ok {
+			t.Errorf(mes+" (case no.%d)", append(args, num)...)
+		}
+	}
+
+	// appending to a batch leaves the next batch unchanged
+	batches[0].Values(9, "z")
+	cases[1].stmt = batches[1]
+	if mes, args, ok := cases[1].Run(); !ok {
+		t.Errorf(mes, args...)
+	}
+
+	if batches = Insert(table1).Values(1, "a").Values(2, "b").Batches(); len(batches) != 1 {
+		t.Errorf("failed \nexpected 1 batch, got %d", len(batches))
+	}
+
+	// the bind variables of expressions in DO UPDATE SET are counted too
+	upsert := insert(table1, limitedDialect{}).
+		Values(1, "a").Values(2, "b").
+		OnConflict(table1.C("id")).
+		DoUpdateSet(table1.C("str"), Concat(Excluded(table1.C("str")), "-", "x"))
+	if _, _, err := upsert.ToSql(); err == nil || err.Error() != "sqlbuilder: 6 bind variables needed, but testing allows 5. (see InsertBuilder.Batches)" {
+		t.Errorf("failed \ngot %v", err)
+	}
+	if batches = upsert.Batches(); len(batches) != 2 {
+		t.Errorf("failed \nexpected 2 batches, got %d", len(batches))
+	}
+	for num, batch := range batches {
+		if _, _, err := batch.ToSql(); err != nil {
+			t.Errorf("failed \nbatch %d: %s", num, err)
+		}
+	}
+}
@@ -140,3 +140,32 @@ func (c *cLiteralImpl) Describe() (output string) {
 	// not implemented yet
 	return
 }
+
+// sqlLiteral returns the SQL literal of a converted value in the dialect
+func sqlLiteral(d Dialect, val interface{}) (string, error) {
+	switch t := val.(type) {
+	case int64:
+		return strconv.FormatInt(t, 10), nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	case bool:
+		if t {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case string:
+		return d.QuoteString(t), nil
+	case time.Time:
+		return d.QuoteString(t.Format("2006-01-02 15:04:05")), nil
+	case sqldriver.Valuer:
+		v, err := t.Value()
+		if err != nil {
+			return "", err
+		}
+		return sqlLiteral(d, v)
+	case nil:
+		return "NULL", nil
+	default:
+		return "", newError("got %T type, but inlined literal is not supporting this.", t)
+	}
+}
@@ -0,0 +1,390 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// ErrMigrationLocked is returned when migrating while another Migrator holds
+// the lock of the schema history
+var ErrMigrationLocked error = newError("migrations are locked by another process.")
+
+// Migration is one version of a schema, with the statements applying and
+// reverting it
+type Migration struct {
+	// Version orders the migrations, it must be positive and unique
+	Version     int64
+	Description string
+	Up          []Statement
+	// Down reverts Up, the migration can not be reverted when empty
+	Down []Statement
+}
+
+// MigrationStatus is the state of one migration
+type MigrationStatus struct {
+	Version     int64
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+	// Registered is false for the versions applied which the Migrator does
+	// not know
+	Registered bool
+}
+
+// Migrator applies and reverts versioned migrations, recording the versions
+// applied in a schema history table created in the dialect of the Executor.
+//
+// Migrations are applied one at a time, each within a transaction together
+// with its history record. A lock table (the history table name suffixed
+// with "_lock") prevents two processes from migrating concurrently; when a
+// process dies while migrating, Unlock releases its lock.
+type Migrator interface {
+	// HistoryTable returns the table recording the migrations applied
+	HistoryTable() Table
+
+	// Migrations returns the migrations registered, in version order
+	Migrations() []Migration
+
+	// DryRun returns a Migrator writing the statements to w instead of
+	// running them, which does not lock or modify the database
+	DryRun(w io.Writer) Migrator
+
+	// Status returns the state of the migrations registered and applied, in
+	// version order. A missing history table has no migrations applied, and
+	// is not created
+	Status(ctx context.Context) ([]MigrationStatus, error)
+
+	// Up applies all pending migrations
+	Up(ctx context.Context) error
+	// UpTo applies the pending migrations up to and including the version
+	UpTo(ctx context.Context, version int64) error
+
+	// Down reverts the last migration applied
+	Down(ctx context.Context) error
+	// DownTo reverts the migrations applied after the version, zero reverts
+	// all of them
+	DownTo(ctx context.Context, version int64) error
+
+	// Unlock releases the lock left by a process which stopped migrating
+	Unlock(ctx context.Context) error
+}
+
+type migrator struct {
+	e          Executor
+	history    Table
+	lock       Table
+	migrations []Migration
+	dryRun     io.Writer
+}
+
+// NewMigrator constructs a new Migrator running the migrations given with the
+// Executor, recording them in the history table named
+func NewMigrator(e Executor, history string, migrations ...Migration) (Migrator, error) {
+	if len(history) == 0 {
+		return nil, newError("history table name is required.")
+	}
+	sorted := append([]Migration(nil), migrations...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	for idx, m := range sorted {
+		if m.Version <= 0 {
+			return nil, newError("migration version %d is not positive.", m.Version)
+		} else if idx > 0 && sorted[idx-1].Version == m.Version {
+			return nil, newError("migration version %d is registered twice.", m.Version)
+		}
+	}
+	return &migrator{
+		e: e,
+		history: NewTable(
+			history,
+			nil,
+			IntColumn("version", &ColumnOption{PrimaryKey: true}),
+			StringColumn("description", &ColumnOption{Size: 255, NotNull: true}),
+			DateColumn("applied_at", &ColumnOption{NotNull: true}),
+		),
+		lock: NewTable(
+			history+"_lock",
+			nil,
+			IntColumn("id", &ColumnOption{PrimaryKey: true}),
+			DateColumn("locked_at", &ColumnOption{NotNull: true}),
+		),
+		migrations: sorted,
+	}, nil
+}
+
+func (m *migrator) HistoryTable() Table {
+	return m.history
+}
+
+func (m *migrator) Migrations() []Migration {
+	return append([]Migration(nil), m.migrations...)
+}
+
+func (m *migrator) DryRun(w io.Writer) Migrator {
+	dry := *m
+	dry.dryRun = w
+	return &dry
+}
+
+func (m *migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	var status []MigrationStatus
+	for _, mg := range m.migrations {
+		s := MigrationStatus{Version: mg.Version, Description: mg.Description, Registered: true}
+		if a, ok := applied[mg.Version]; ok {
+			s.Applied, s.AppliedAt = true, a.AppliedAt
+			delete(applied, mg.Version)
+		}
+		status = append(status, s)
+	}
+	for _, a := range applied {
+		status = append(status, a)
+	}
+	sort.SliceStable(status, func(i, j int) bool {
+		return status[i].Version < status[j].Version
+	})
+	return status, nil
+}
+
+func (m *migrator) Up(ctx context.Context) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.UpTo(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+func (m *migrator) UpTo(ctx context.Context, version int64) error {
+	return m.locked(ctx, func(applied map[int64]MigrationStatus) error {
+		for _, mg := range m.migrations {
+			if mg.Version > version {
+				break
+			} else if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			record := m.e.Insert(m.history).
+				Values(mg.Version, mg.Description, time.Now().UTC().Truncate(time.Second))
+			if err := m.run(ctx, mg, mg.Up, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (m *migrator) Down(ctx context.Context) error {
+	return m.locked(ctx, func(applied map[int64]MigrationStatus) error {
+		last := int64(0)
+		for version := range applied {
+			if version > last {
+				last = version
+			}
+		}
+		if last == 0 {
+			return nil
+		} else if !applied[last].Registered {
+			return newError("migration %d is not registered.", last)
+		}
+		return m.revert(ctx, applied, last-1)
+	})
+}
+
+func (m *migrator) DownTo(ctx context.Context, version int64) error {
+	return m.locked(ctx, func(applied map[int64]MigrationStatus) error {
+		return m.revert(ctx, applied, version)
+	})
+}
+
+func (m *migrator) Unlock(ctx context.Context) error {
+	_, err := m.e.ExecContext(ctx, m.e.Delete(m.lock))
+	return err
+}
+
+// revert runs the Down statements of the migrations applied after the
+// version given, newest first
+func (m *migrator) revert(ctx context.Context, applied map[int64]MigrationStatus, version int64) error {
+	for idx := len(m.migrations) - 1; idx >= 0; idx-- {
+		mg := m.migrations[idx]
+		if mg.Version <= version {
+			break
+		} else if _, ok := applied[mg.Version]; !ok {
+			continue
+		} else if len(mg.Down) == 0 {
+			return newError("migration %d can not be reverted.", mg.Version)
+		}
+		record := m.e.Delete(m.history).Where(m.history.C("version").Eq(mg.Version))
+		if err := m.run(ctx, mg, mg.Down, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run executes the statements of the migration and its history record in
+// a transaction, or writes them when dry-running
+func (m *migrator) run(ctx context.Context, mg Migration, statements []Statement, record Statement) error {
+	statements = append(append([]Statement(nil), statements...), record)
+	if m.dryRun != nil {
+		if _, err := fmt.Fprintf(m.dryRun, "-- migration %d: %s\n", mg.Version, mg.Description); err != nil {
+			return err
+		}
+		return m.write(statements...)
+	}
+	return Transaction(ctx, m.e, nil, func(tx Executor) error {
+		for _, stmt := range statements {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// write writes the queries of the statements to the dry-run writer
+func (m *migrator) write(statements ...Statement) error {
+	for _, stmt := range statements {
+		split, err := splitStatement(stmt)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range split {
+			query, args, err := stmt.ToSql()
+			if err != nil {
+				return err
+			}
+			if len(args) != 0 {
+				query += fmt.Sprintf(" -- args: %v", args)
+			}
+			if _, err = fmt.Fprintln(m.dryRun, query); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// locked calls fn with the migrations applied, holding the lock unless
+// dry-running
+func (m *migrator) locked(ctx context.Context, fn func(applied map[int64]MigrationStatus) error) (err error) {
+	if err = m.createTables(ctx); err != nil {
+		return err
+	}
+	if m.dryRun == nil {
+		if _, err = m.e.ExecContext(ctx, m.e.Insert(m.lock).Values(1, time.Now().UTC().Truncate(time.Second))); err != nil {
+			var count int64
+			row := m.e.QueryRowContext(ctx, m.e.Select(m.lock).Columns(Func("COUNT", m.lock.C("id"))))
+			if e := row.Scan(&count); e == nil && count != 0 {
+				return ErrMigrationLocked
+			}
+			return err
+		}
+		defer func() {
+			if e := m.Unlock(context.WithoutCancel(ctx)); e != nil && err == nil {
+				err = e
+			}
+		}()
+	}
+	applied, err := m.applied(ctx, m.dryRun != nil)
+	if err != nil {
+		return err
+	}
+	for _, mg := range m.migrations {
+		if s, ok := applied[mg.Version]; ok {
+			s.Registered = true
+			applied[mg.Version] = s
+		}
+	}
+	return fn(applied)
+}
+
+// createTables creates the history and lock tables when missing, or writes
+// the statements when dry-running
+func (m *migrator) createTables(ctx context.Context) error {
+	statements := []Statement{
+		m.e.CreateTable(m.history).IfNotExists(),
+		m.e.CreateTable(m.lock).IfNotExists(),
+	}
+	if m.dryRun != nil {
+		return m.write(statements...)
+	}
+	for _, stmt := range statements {
+		if _, err := m.e.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applied returns the migrations recorded in the history table. When
+// missing is true, such as when dry-running, the history table may not exist
+// yet and then has no migrations
+func (m *migrator) applied(ctx context.Context, missing bool) (map[int64]MigrationStatus, error) {
+	if missing {
+		var count int64
+		query := m.e.Dialect().TableExistsToString()
+		if err := m.e.Querier().QueryRowContext(ctx, query, m.history.Name()).Scan(&count); err != nil {
+			return nil, err
+		} else if count == 0 {
+			return map[int64]MigrationStatus{}, nil
+		}
+	}
+	stmt := m.e.Select(m.history).
+		Columns(m.history.C("version"), m.history.C("description"), m.history.C("applied_at"))
+	rows, err := m.e.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := map[int64]MigrationStatus{}
+	for rows.Next() {
+		var s MigrationStatus
+		var at interface{}
+		if err = rows.Scan(&s.Version, &s.Description, &at); err != nil {
+			return nil, err
+		}
+		s.Applied = true
+		switch t := at.(type) {
+		case time.Time:
+			s.AppliedAt = t
+		case []byte:
+			if s.AppliedAt, err = time.Parse("2006-01-02 15:04:05", string(t)); err != nil {
+				return nil, err
+			}
+		case string:
+			if s.AppliedAt, err = time.Parse("2006-01-02 15:04:05", t); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, newError("migration %d has an applied_at of unsupported type %T.", s.Version, at)
+		}
+		applied[s.Version] = s
+	}
+	return applied, rows.Err()
+}
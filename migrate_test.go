@@ -0,0 +1,214 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMigrator(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	ex := NewExecutor(db, NewBuildable(TestingDialect{}))
+
+	people := NewTable(
+		"PEOPLE",
+		nil,
+		IntColumn("id", &ColumnOption{PrimaryKey: true}),
+		StringColumn("name", nil),
+	)
+	groups := NewTable(
+		"GROUPS",
+		nil,
+		IntColumn("id", &ColumnOption{PrimaryKey: true}),
+	)
+	migrations := []Migration{{
+		Version:     2,
+		Description: "add groups",
+		Up:          []Statement{ex.CreateTable(groups)},
+		Down:        []Statement{ex.DropTable(groups)},
+	}, {
+		Version:     1,
+		Description: "add people",
+		Up: []Statement{
+			ex.CreateTable(people),
+			ex.Insert(people).Values(1, "one"),
+		},
+		Down: []Statement{ex.DropTable(people)},
+	}}
+	versions := func(status []MigrationStatus) (applied []int64) {
+		for _, s := range status {
+			if s.Applied {
+				applied = append(applied, s.Version)
+			}
+		}
+		return
+	}
+
+	Convey("NewMigrator", t, func() {
+		_, err := NewMigrator(ex, "")
+		So(err, ShouldNotBeNil)
+		_, err = NewMigrator(ex, "HISTORY", Migration{Version: 0})
+		So(err.Error(), ShouldEqual, "sqlbuilder: migration version 0 is not positive.")
+		_, err = NewMigrator(ex, "HISTORY", Migration{Version: 1}, Migration{Version: 1})
+		So(err.Error(), ShouldEqual, "sqlbuilder: migration version 1 is registered twice.")
+	})
+
+	m, err := NewMigrator(ex, "HISTORY", migrations...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("DryRun", t, func() {
+		So(m.Migrations()[0].Version, ShouldEqual, 1)
+
+		var buf bytes.Buffer
+		So(m.DryRun(&buf).Up(ctx), ShouldBeNil)
+		So(buf.String(), ShouldStartWith, `CREATE TABLE IF NOT EXISTS "HISTORY" ( "version" INTEGER PRIMARY KEY, `+
+			`"description" TEXT NOT NULL, "applied_at" DATE NOT NULL );`+"\n")
+		So(buf.String(), ShouldContainSubstring, "-- migration 1: add people\n"+
+			`CREATE TABLE "PEOPLE" ( "id" INTEGER PRIMARY KEY, "name" TEXT );`+"\n"+
+			`INSERT INTO "PEOPLE" ( "id", "name" ) VALUES ( ?, ? ); -- args: [1 one]`+"\n")
+		So(buf.String(), ShouldContainSubstring, "-- migration 2: add groups\n")
+
+		status, err := m.Status(ctx)
+		So(err, ShouldBeNil)
+		So(len(status), ShouldEqual, 2)
+		So(versions(status), ShouldBeEmpty)
+		var count int64
+		So(db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'HISTORY'`).Scan(&count), ShouldBeNil)
+		So(count, ShouldEqual, 0)
+	})
+
+	Convey("Up", t, func() {
+		So(m.UpTo(ctx, 1), ShouldBeNil)
+		status, err := m.Status(ctx)
+		So(err, ShouldBeNil)
+		So(versions(status), ShouldResemble, []int64{1})
+
+		So(m.Up(ctx), ShouldBeNil)
+		status, err = m.Status(ctx)
+		So(err, ShouldBeNil)
+		So(versions(status), ShouldResemble, []int64{1, 2})
+		So(status[0].Description, ShouldEqual, "add people")
+		So(status[0].AppliedAt.IsZero(), ShouldBeFalse)
+		So(status[0].Registered, ShouldBeTrue)
+
+		var name string
+		So(ex.QueryRow(ex.Select(people).Columns(people.C("name"))).Scan(&name), ShouldBeNil)
+		So(name, ShouldEqual, "one")
+	})
+
+	Convey("Lock", t, func() {
+		_, err := db.Exec(`INSERT INTO "HISTORY_lock" ( "id", "locked_at" ) VALUES ( 1, CURRENT_TIMESTAMP )`)
+		So(err, ShouldBeNil)
+		So(m.Down(ctx), ShouldEqual, ErrMigrationLocked)
+		So(m.Unlock(ctx), ShouldBeNil)
+		So(m.Down(ctx), ShouldBeNil)
+		status, err := m.Status(ctx)
+		So(err, ShouldBeNil)
+		So(versions(status), ShouldResemble, []int64{1})
+	})
+
+	Convey("Down", t, func() {
+		So(m.Up(ctx), ShouldBeNil)
+		So(m.DownTo(ctx, 0), ShouldBeNil)
+		status, err := m.Status(ctx)
+		So(err, ShouldBeNil)
+		So(versions(status), ShouldBeEmpty)
+		So(m.Down(ctx), ShouldBeNil)
+
+		irreversible, err := NewMigrator(ex, "HISTORY", append(migrations, Migration{
+			Version: 3,
+			Up:      []Statement{ex.Insert(people).Values(2, "two")},
+		})...)
+		So(err, ShouldBeNil)
+		So(irreversible.Up(ctx), ShouldBeNil)
+		So(irreversible.Down(ctx).Error(), ShouldEqual, "sqlbuilder: migration 3 can not be reverted.")
+
+		unknown, err := NewMigrator(ex, "HISTORY", migrations...)
+		So(err, ShouldBeNil)
+		So(unknown.Down(ctx).Error(), ShouldEqual, "sqlbuilder: migration 3 is not registered.")
+		status, err = unknown.Status(ctx)
+		So(err, ShouldBeNil)
+		So(versions(status), ShouldResemble, []int64{1, 2, 3})
+		So(status[2].Registered, ShouldBeFalse)
+	})
+
+	Convey("Failure", t, func() {
+		broken, err := NewMigrator(ex, "BROKEN_HISTORY", Migration{
+			Version: 1,
+			Up: []Statement{
+				ex.Insert(people).Values(10, "ten"),
+				ex.Insert(people).Values(10, "ten"),
+			},
+		})
+		So(err, ShouldBeNil)
+		So(broken.Up(ctx), ShouldNotBeNil)
+		status, err := broken.Status(ctx)
+		So(err, ShouldBeNil)
+		So(versions(status), ShouldBeEmpty)
+
+		var count int64
+		So(ex.QueryRow(ex.Select(people).Columns(Func("COUNT", people.C("id"))).Where(people.C("id").Eq(10))).Scan(&count), ShouldBeNil)
+		So(count, ShouldEqual, 0)
+
+		// the applied_at values read as text must be parsed
+		_, err = db.Exec(`CREATE TABLE "TEXT_HISTORY" ( "version" INTEGER PRIMARY KEY, "description" TEXT, "applied_at" TEXT )`)
+		So(err, ShouldBeNil)
+		_, err = db.Exec(`INSERT INTO "TEXT_HISTORY" VALUES ( 1, 'bad', 'yesterday' )`)
+		So(err, ShouldBeNil)
+		text, err := NewMigrator(ex, "TEXT_HISTORY")
+		So(err, ShouldBeNil)
+		_, err = text.Status(ctx)
+		So(err, ShouldNotBeNil)
+
+		// unix timestamps are not supported
+		_, err = db.Exec(`CREATE TABLE "UNIX_HISTORY" ( "version" INTEGER PRIMARY KEY, "description" TEXT, "applied_at" INTEGER )`)
+		So(err, ShouldBeNil)
+		_, err = db.Exec(`INSERT INTO "UNIX_HISTORY" VALUES ( 1, 'unix', 1700000000 )`)
+		So(err, ShouldBeNil)
+		unix, err := NewMigrator(ex, "UNIX_HISTORY")
+		So(err, ShouldBeNil)
+		_, err = unix.Status(ctx)
+		So(err.Error(), ShouldEqual, "sqlbuilder: migration 1 has an applied_at of unsupported type int64.")
+
+		// only a missing history table has no migrations
+		_, err = db.Exec(`CREATE TABLE "OTHER_HISTORY" ( "id" INTEGER PRIMARY KEY )`)
+		So(err, ShouldBeNil)
+		other, err := NewMigrator(ex, "OTHER_HISTORY")
+		So(err, ShouldBeNil)
+		_, err = other.Status(ctx)
+		So(err, ShouldNotBeNil)
+	})
+}
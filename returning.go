@@ -0,0 +1,46 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+// checkReturning validates the RETURNING columns are all present in the table
+// being modified
+func checkReturning(tbl Table, columns []Column) (list cSelectColumnList, err error) {
+	for _, col := range columns {
+		if !tbl.hasColumn(col) {
+			return nil, newError("column not found in table: %q", col.column_name())
+		}
+	}
+	return append(cSelectColumnList{}, columns...), nil
+}
+
+// serializeReturning writes the RETURNING clause, if any columns were given
+func serializeReturning(b *builder, columns cSelectColumnList) {
+	if columns == nil {
+		return
+	}
+	if !b.dialect.SupportsReturning() {
+		b.SetError(newError("RETURNING is not supported by the %s dialect.", b.dialect.Name()))
+		return
+	}
+	b.Append(" RETURNING ")
+	b.AppendItem(columns)
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+)
+
+// ScanTag is the struct field tag naming the column scanned into the field,
+// "-" skips the field. Untagged fields match columns of the same name, case
+// insensitively.
+const ScanTag = "sql"
+
+// resultStatement is a statement selecting a known list of columns
+type resultStatement interface {
+	Statement
+	resultColumns() []Column
+}
+
+// ScanRow copies the current row of rows into dest, which is one of:
+//
+//   - a pointer to a struct, with a field for each column selected
+//   - a map[string]interface{}, or a pointer to one, keyed by column name
+//   - a pointer to a []interface{}, in the order the columns were selected
+//
+// The column names are those given to the SELECT statement stmt, using the As
+// alias of a column when set and the function name of a Func column.
+func ScanRow(rows *sql.Rows, stmt Statement, dest interface{}) error {
+	names, err := scanColumnNames(rows, stmt)
+	if err != nil {
+		return err
+	}
+	return scanRow(rows, names, reflect.ValueOf(dest))
+}
+
+// ScanRows copies all the rows of rows into dest, a pointer to a slice of any
+// of the types accepted by ScanRow, or of structs. The rows are closed once
+// read.
+func ScanRows(rows *sql.Rows, stmt Statement, dest interface{}) error {
+	defer rows.Close()
+	names, err := scanColumnNames(rows, stmt)
+	if err != nil {
+		return err
+	}
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return newError("got %T type, but ScanRows needs a pointer to a slice.", dest)
+	}
+	slice = slice.Elem()
+	typ := slice.Type().Elem()
+	for rows.Next() {
+		var item reflect.Value
+		switch {
+		case typ.Kind() == reflect.Ptr:
+			item = reflect.New(typ.Elem())
+		case typ.Kind() == reflect.Map:
+			item = reflect.MakeMap(typ)
+		default:
+			item = reflect.New(typ)
+		}
+		if err = scanRow(rows, names, item); err != nil {
+			return err
+		}
+		if typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Map {
+			item = item.Elem()
+		}
+		slice.Set(reflect.Append(slice, item))
+	}
+	return rows.Err()
+}
+
+// scanColumnNames returns the names of the columns selected by the statement,
+// checking them against the columns of the rows
+func scanColumnNames(rows *sql.Rows, stmt Statement) ([]string, error) {
+	if err := checkResultStatement(stmt); err != nil {
+		return nil, err
+	}
+	columns := stmt.(resultStatement).resultColumns()
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		if err := GetColumnError(col); err != nil {
+			return nil, err
+		}
+		names[i] = col.column_name()
+	}
+	got, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if len(got) != len(names) {
+		return nil, newError("%d columns selected, but got %d.", len(names), len(got))
+	}
+	return names, nil
+}
+
+func checkResultStatement(stmt Statement) error {
+	if _, ok := stmt.(resultStatement); !ok {
+		return newError("got %T type, but the statement has no known result columns.", stmt)
+	}
+	return nil
+}
+
+func scanRow(rows *sql.Rows, names []string, dest reflect.Value) error {
+	if dest.Kind() == reflect.Ptr && dest.Elem().Kind() == reflect.Map {
+		if dest.Elem().IsNil() {
+			dest.Elem().Set(reflect.MakeMap(dest.Elem().Type()))
+		}
+		dest = dest.Elem()
+	}
+
+	switch {
+	case dest.Kind() == reflect.Map:
+		if dest.Type().Key().Kind() != reflect.String || dest.Type().Elem().Kind() != reflect.Interface {
+			return newError("got %s type, but maps must be map[string]interface{}.", dest.Type())
+		}
+		values, err := scanValues(rows, len(names))
+		if err != nil {
+			return err
+		}
+		for i, name := range names {
+			dest.SetMapIndex(reflect.ValueOf(name), reflect.ValueOf(&values[i]).Elem())
+		}
+		return nil
+
+	case dest.Kind() == reflect.Ptr && dest.Elem().Kind() == reflect.Slice:
+		if dest.Elem().Type().Elem().Kind() != reflect.Interface {
+			return newError("got %s type, but slices must be []interface{}.", dest.Elem().Type())
+		}
+		values, err := scanValues(rows, len(names))
+		if err != nil {
+			return err
+		}
+		dest.Elem().Set(reflect.ValueOf(values))
+		return nil
+
+	case dest.Kind() == reflect.Ptr && dest.Elem().Kind() == reflect.Struct:
+		fields := scanFields(dest.Elem().Type())
+		targets := make([]interface{}, len(names))
+		for i, name := range names {
+			index, ok := fields[strings.ToLower(name)]
+			if !ok {
+				return newError("column %q has no destination field in %s.", name, dest.Elem().Type())
+			}
+			targets[i] = dest.Elem().FieldByIndex(index).Addr().Interface()
+		}
+		return rows.Scan(targets...)
+	}
+	return newError("got %s type, but can only scan into struct pointers, maps and slices.", dest.Type())
+}
+
+// scanValues scans the current row into a list of values, copying any bytes
+// as the driver may reuse them
+func scanValues(rows *sql.Rows, count int) ([]interface{}, error) {
+	values := make([]interface{}, count)
+	targets := make([]interface{}, count)
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	if err := rows.Scan(targets...); err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		if b, ok := value.([]byte); ok {
+			values[i] = append([]byte(nil), b...)
+		}
+	}
+	return values, nil
+}
+
+// scanFields returns the field index of each column name, lowercased, of the
+// struct type given, including the fields of embedded structs
+func scanFields(typ reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := field.Name
+			if tag, ok := field.Tag.Lookup(ScanTag); ok {
+				if name, _, _ = strings.Cut(tag, ","); name == "-" {
+					continue
+				}
+			}
+			path := append(append([]int(nil), index...), i)
+			if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get(ScanTag) == "" {
+				walk(field.Type, path)
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if _, present := fields[strings.ToLower(name)]; !present || len(index) == 0 {
+				fields[strings.ToLower(name)] = path
+			}
+		}
+	}
+	walk(typ, nil)
+	return fields
+}
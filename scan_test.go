@@ -0,0 +1,140 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"database/sql"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type scanBase struct {
+	Id int64 `sql:"id"`
+}
+
+type scanPerson struct {
+	scanBase
+	Name    string `sql:"name"`
+	Age     sql.NullInt64
+	Ignored string `sql:"-"`
+	secret  string
+}
+
+type scanCount struct {
+	Total int64  `sql:"total"`
+	Max   string `sql:"MAX"`
+}
+
+func TestScan(t *testing.T) {
+	tbl := NewTable(
+		"PERSON",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		StringColumn("name", nil),
+		IntColumn("age", nil),
+	)
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ex := NewExecutor(db, NewBuildable(TestingDialect{}))
+	if _, err = ex.Exec(ex.CreateTable(tbl)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ex.Exec(ex.Insert(tbl).Values(1, "Alice", 30).Values(2, "Bob", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Structs", t, func() {
+		var person scanPerson
+		So(ex.ScanOne(ex.Select(tbl).Where(tbl.C("id").Eq(1)), &person), ShouldBeNil)
+		So(person.Id, ShouldEqual, 1)
+		So(person.Name, ShouldEqual, "Alice")
+		So(person.Age, ShouldResemble, sql.NullInt64{Int64: 30, Valid: true})
+
+		var people []scanPerson
+		So(ex.ScanAll(ex.Select(tbl).OrderBy(false, tbl.C("id")), &people), ShouldBeNil)
+		So(len(people), ShouldEqual, 2)
+		So(people[1].Name, ShouldEqual, "Bob")
+		So(people[1].Age.Valid, ShouldBeFalse)
+
+		var pointers []*scanPerson
+		So(ex.ScanAll(ex.Select(tbl).Columns(tbl.C("name"), tbl.C("id")), &pointers), ShouldBeNil)
+		So(len(pointers), ShouldEqual, 2)
+		So(pointers[0].Id, ShouldNotEqual, 0)
+	})
+
+	Convey("Aliases and functions", t, func() {
+		var count scanCount
+		stmt := ex.Select(tbl).Columns(Func("COUNT", tbl.C("id")).As("total"), Func("MAX", tbl.C("name")))
+		So(ex.ScanOne(stmt, &count), ShouldBeNil)
+		So(count, ShouldResemble, scanCount{Total: 2, Max: "Bob"})
+
+		var person scanPerson
+		err := ex.ScanOne(ex.Select(tbl).Columns(tbl.C("name").As("nickname")), &person)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, `sqlbuilder: column "nickname" has no destination field in sqlbuilder.scanPerson.`)
+	})
+
+	Convey("Maps and slices", t, func() {
+		var row map[string]interface{}
+		So(ex.ScanOne(ex.Select(tbl).Columns(tbl.C("id"), tbl.C("name").As("n")).Where(tbl.C("id").Eq(2)), &row), ShouldBeNil)
+		So(row, ShouldResemble, map[string]interface{}{"id": int64(2), "n": "Bob"})
+
+		var rows []map[string]interface{}
+		So(ex.ScanAll(ex.Select(tbl).Columns(tbl.C("age")).OrderBy(false, tbl.C("id")), &rows), ShouldBeNil)
+		So(rows, ShouldResemble, []map[string]interface{}{{"age": int64(30)}, {"age": nil}})
+
+		var values [][]interface{}
+		So(ex.ScanAll(ex.Select(tbl).Columns(tbl.C("name"), tbl.C("id")).OrderBy(false, tbl.C("id")), &values), ShouldBeNil)
+		So(values, ShouldResemble, [][]interface{}{{"Alice", int64(1)}, {"Bob", int64(2)}})
+	})
+
+	Convey("Compound selects", t, func() {
+		var names []map[string]interface{}
+		stmt := Union(
+			ex.Select(tbl).Columns(tbl.C("name")).Where(tbl.C("id").Eq(1)),
+			ex.Select(tbl).Columns(tbl.C("name")).Where(tbl.C("id").Eq(2)),
+		).OrderBy(false, tbl.C("name"))
+		So(ex.ScanAll(stmt, &names), ShouldBeNil)
+		So(names, ShouldResemble, []map[string]interface{}{{"name": "Alice"}, {"name": "Bob"}})
+	})
+
+	Convey("Errors", t, func() {
+		var person scanPerson
+		err := ex.ScanOne(ex.Select(tbl).Where(tbl.C("id").Eq(3)), &person)
+		So(err, ShouldWrap, sql.ErrNoRows)
+
+		var people []scanPerson
+		So(ex.ScanAll(ex.Select(tbl), people), ShouldNotBeNil)
+		So(ex.ScanAll(ex.Delete(tbl), &people), ShouldNotBeNil)
+		So(ex.ScanAll(ex.Select(tbl), &people), ShouldBeNil)
+		So(len(people), ShouldEqual, 2)
+		So(ex.ScanOne(ex.Select(tbl), person), ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,406 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SchemaChangeType is the type of a SchemaChange
+type SchemaChangeType int
+
+const (
+	SchemaCreateTable SchemaChangeType = iota
+	SchemaRenameTable
+	SchemaDropTable
+	SchemaAddColumn
+	SchemaChangeColumn
+	SchemaDropColumn
+	SchemaAddUnique
+	SchemaDropUnique
+)
+
+func (t SchemaChangeType) String() string {
+	switch t {
+	case SchemaCreateTable:
+		return "create table"
+	case SchemaRenameTable:
+		return "rename table"
+	case SchemaDropTable:
+		return "drop table"
+	case SchemaAddColumn:
+		return "add column"
+	case SchemaChangeColumn:
+		return "change column"
+	case SchemaDropColumn:
+		return "drop column"
+	case SchemaAddUnique:
+		return "add unique"
+	case SchemaDropUnique:
+		return "drop unique"
+	}
+	return fmt.Sprintf("SchemaChangeType(%d)", int(t))
+}
+
+// SchemaChange is one difference found by DiffSchema
+type SchemaChange struct {
+	Type SchemaChangeType
+	// Table is the name of the table changed, the new name for created
+	// tables and the old name for renamed tables
+	Table string
+	// Name is the name of the column changed, or the new name of a renamed
+	// table
+	Name string
+	// Columns are the columns of the unique constraint changed
+	Columns []string
+}
+
+func (c SchemaChange) String() string {
+	switch c.Type {
+	case SchemaRenameTable:
+		return c.Type.String() + " " + c.Table + " to " + c.Name
+	case SchemaAddColumn, SchemaChangeColumn, SchemaDropColumn:
+		return c.Type.String() + " " + c.Table + "." + c.Name
+	case SchemaAddUnique, SchemaDropUnique:
+		return c.Type.String() + " " + c.Table + " (" + strings.Join(c.Columns, ", ") + ")"
+	}
+	return c.Type.String() + " " + c.Table
+}
+
+// SchemaDiff is the difference between two sets of tables, with the
+// statements migrating from one to the other
+type SchemaDiff struct {
+	// Changes are the differences migrated by the Statements, in the order
+	// of the Statements migrating them. One ALTER TABLE statement migrates
+	// all the column changes of a table
+	Changes []SchemaChange
+	// Statements are the CREATE TABLE, ALTER TABLE, CREATE UNIQUE INDEX and
+	// DROP TABLE statements migrating the tables, in order. An ALTER TABLE
+	// statement may need several statements of the dialect, as run by
+	// Executor (see AlterTableBuilder.Statements)
+	Statements []Statement
+	// Unmigrated are the differences found which the Statements do not
+	// migrate, such as the unique keys removed
+	Unmigrated []SchemaChange
+}
+
+// DiffOptions are the options of DiffSchema
+type DiffOptions struct {
+	// DetectRenames reports a table removed and one added with the same
+	// column definitions as renamed, instead of dropped and created
+	DetectRenames bool
+}
+
+// DiffSchema compares the tables given, such as the tables introspected from
+// a database and the tables declared in code, returning the changes needed
+// to migrate from one to the other built with the Buildable given. The
+// default options are used when opts is nil.
+//
+// Columns are compared by their definitions in the dialect of the Buildable,
+// while their order is ignored. Unique column options and unique constraints
+// are compared as unique keys, regardless of the order of their columns.
+// Unique keys removed are reported in Unmigrated, as they are named by the
+// database. Primary keys, foreign keys and
+// checks are not compared. Returns an error when the column changes of a
+// table can not be migrated by ALTER TABLE in the dialect, such as with
+// SQLite (see AlterTableBuilder.Rebuild).
+func DiffSchema(b Buildable, from, to []Table, opts *DiffOptions) (*SchemaDiff, error) {
+	if opts == nil {
+		opts = &DiffOptions{}
+	}
+	d := b.Dialect()
+	natural := func(tables []Table) ([]*cTable, error) {
+		list := make([]*cTable, 0, len(tables))
+		for _, tbl := range tables {
+			t, ok := tbl.(*cTable)
+			if !ok {
+				return nil, newError("DiffSchema can use only natural tables.")
+			}
+			list = append(list, t)
+		}
+		return list, nil
+	}
+	fromTables, err := natural(from)
+	if err != nil {
+		return nil, err
+	}
+	toTables, err := natural(to)
+	if err != nil {
+		return nil, err
+	}
+	findTable := func(tables []*cTable, name string) *cTable {
+		for _, t := range tables {
+			if t.name == name {
+				return t
+			}
+		}
+		return nil
+	}
+
+	diff := &SchemaDiff{}
+	var created, dropped []*cTable
+	for _, t := range toTables {
+		if findTable(fromTables, t.name) == nil {
+			created = append(created, t)
+		}
+	}
+	for _, t := range fromTables {
+		if findTable(toTables, t.name) == nil {
+			dropped = append(dropped, t)
+		}
+	}
+
+	// a table dropped with the same definition as one created is renamed
+	renamed := map[*cTable]*cTable{}
+	for _, t := range created {
+		if !opts.DetectRenames {
+			break
+		}
+		signature, err := diffTableSignature(d, t)
+		if err != nil {
+			return nil, err
+		}
+		for _, old := range dropped {
+			if _, ok := renamed[old]; ok {
+				continue
+			}
+			if s, err := diffTableSignature(d, old); err != nil {
+				return nil, err
+			} else if s == signature {
+				renamed[old] = t
+				renamed[t] = old
+				break
+			}
+		}
+	}
+
+	for _, t := range created {
+		if _, ok := renamed[t]; ok {
+			continue
+		}
+		diff.add(SchemaChange{Type: SchemaCreateTable, Table: t.name}, createTable(t, d))
+	}
+	for _, old := range fromTables {
+		if t, ok := renamed[old]; ok {
+			diff.add(SchemaChange{Type: SchemaRenameTable, Table: old.name, Name: t.name}, alterTable(old, d).RenameTo(t.name))
+		} else if t = findTable(toTables, old.name); t != nil {
+			if err = diff.diffTable(d, old, t); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for idx := len(dropped) - 1; idx >= 0; idx-- {
+		if _, ok := renamed[dropped[idx]]; ok {
+			continue
+		}
+		diff.add(SchemaChange{Type: SchemaDropTable, Table: dropped[idx].name}, dropTable(dropped[idx], d))
+	}
+	return diff, nil
+}
+
+// Empty reports whether no changes were found
+func (s *SchemaDiff) Empty() bool {
+	return len(s.Changes) == 0 && len(s.Unmigrated) == 0
+}
+
+// Apply returns copies of the tables given with the Statements applied, to
+// verify that the migration converges. The tables given are not modified
+func (s *SchemaDiff) Apply(tables []Table) ([]Table, error) {
+	applied := make([]*cTable, 0, len(tables))
+	for _, tbl := range tables {
+		t, ok := tbl.(*cTable)
+		if !ok {
+			return nil, newError("Apply can use only natural tables.")
+		}
+		applied = append(applied, t.clone())
+	}
+	index := func(name string) int {
+		for idx, t := range applied {
+			if t.name == name {
+				return idx
+			}
+		}
+		return -1
+	}
+	for _, stmt := range s.Statements {
+		switch st := stmt.(type) {
+		case *cCreateTable:
+			applied = append(applied, st.table.(*cTable).clone())
+		case *cDropTable:
+			if idx := index(st.table.Name()); idx >= 0 {
+				applied = append(applied[:idx], applied[idx+1:]...)
+			}
+		case *cAlterTable:
+			idx := index(st.table.name)
+			if idx < 0 {
+				return nil, newError("table %s was not found.", st.table.name)
+			}
+			if err := st.applyTo(applied[idx]); err != nil {
+				return nil, err
+			}
+		case *cCreateIndex:
+			idx := index(st.table.Name())
+			if idx < 0 {
+				return nil, newError("table %s was not found.", st.table.Name())
+			}
+			var columns []string
+			for _, col := range st.columns {
+				columns = append(columns, col.column_name())
+			}
+			applied[idx].option.Unique = append(applied[idx].option.Unique, columns)
+		}
+	}
+	result := make([]Table, len(applied))
+	for idx, t := range applied {
+		result[idx] = t
+	}
+	return result, nil
+}
+
+func (s *SchemaDiff) add(change SchemaChange, stmt Statement) {
+	s.Changes = append(s.Changes, change)
+	s.Statements = append(s.Statements, stmt)
+}
+
+// diffTable adds the changes of the columns and unique constraints of the
+// table
+func (s *SchemaDiff) diffTable(d Dialect, from, to *cTable) error {
+	var changes []SchemaChange
+	alter := alterTable(from, d)
+	for _, col := range to.columns {
+		cc := col.config()
+		if !from.hasColumnName(cc.Name()) {
+			// the unique key of the column is added by its own index, as
+			// not every dialect can add a unique column
+			option := *cc.Option()
+			option.Unique = false
+			changes = append(changes, SchemaChange{Type: SchemaAddColumn, Table: from.name, Name: cc.Name()})
+			alter.AddColumn(newColumnImplConfig(cc.Name(), cc.Type(), &option))
+			continue
+		}
+		old := from.C(cc.Name())
+		if a, err := diffColumnDefinition(d, old.config()); err != nil {
+			return err
+		} else if b, err := diffColumnDefinition(d, cc); err != nil {
+			return err
+		} else if a != b {
+			changes = append(changes, SchemaChange{Type: SchemaChangeColumn, Table: from.name, Name: cc.Name()})
+			alter.ChangeColumn(old, cc)
+		}
+	}
+	for _, col := range from.columns {
+		if !to.hasColumnName(col.column_name()) {
+			changes = append(changes, SchemaChange{Type: SchemaDropColumn, Table: from.name, Name: col.column_name()})
+			alter.DropColumn(col)
+		}
+	}
+	if len(changes) != 0 {
+		if _, err := alter.Statements(); err != nil {
+			return newError("table %s can not be altered: %s. (see AlterTableBuilder.Rebuild)", from.name, err)
+		}
+		s.Changes = append(s.Changes, changes...)
+		s.Statements = append(s.Statements, alter)
+	}
+
+	hasUnique := func(list [][]string, columns []string) bool {
+		key := diffUniqueKey(columns)
+		for _, u := range list {
+			if diffUniqueKey(u) == key {
+				return true
+			}
+		}
+		return false
+	}
+	fromUnique, toUnique := diffUniqueKeys(from), diffUniqueKeys(to)
+	for _, columns := range toUnique {
+		if hasUnique(fromUnique, columns) {
+			continue
+		}
+		index := createIndex(to, d).Unique().Name("uq_" + to.name + "_" + strings.Join(columns, "_"))
+		var cols []Column
+		for _, name := range columns {
+			cols = append(cols, to.C(name))
+		}
+		s.add(SchemaChange{Type: SchemaAddUnique, Table: to.name, Columns: columns}, index.Columns(cols...))
+	}
+	for _, columns := range fromUnique {
+		if !hasUnique(toUnique, columns) {
+			s.Unmigrated = append(s.Unmigrated, SchemaChange{Type: SchemaDropUnique, Table: from.name, Columns: columns})
+		}
+	}
+	return nil
+}
+
+// diffUniqueKeys returns the unique constraints of the table, after the
+// columns with the unique option
+func diffUniqueKeys(t *cTable) [][]string {
+	var keys [][]string
+	for _, col := range t.columns {
+		if col.config().Option().Unique {
+			keys = append(keys, []string{col.column_name()})
+		}
+	}
+	return append(keys, t.option.Unique...)
+}
+
+// diffUniqueKey returns the columns of the unique key as a comparable set
+func diffUniqueKey(columns []string) string {
+	sorted := append([]string{}, columns...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "\x00")
+}
+
+// diffColumnDefinition returns the column as declared by the dialect, without
+// the unique option compared by diffUniqueKeys
+func diffColumnDefinition(d Dialect, cc ColumnConfig) (string, error) {
+	typ, err := d.ColumnTypeToString(cc)
+	if err != nil {
+		return "", err
+	}
+	option := *cc.Option()
+	option.Unique = false
+	opt, err := d.ColumnOptionToString(&option)
+	if err != nil {
+		return "", err
+	}
+	return d.QuoteField(cc.Name()) + " " + typ + " " + opt, nil
+}
+
+// diffTableSignature returns the definitions of the columns and the table
+// constraints, without the table name
+func diffTableSignature(d Dialect, t *cTable) (string, error) {
+	var parts []string
+	for _, col := range t.columns {
+		def, err := diffColumnDefinition(d, col.config())
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, def)
+	}
+	opt, err := d.TableOptionToString(t.option)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(append(parts, opt), ", "), nil
+}
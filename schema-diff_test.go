@@ -0,0 +1,185 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"testing"
+)
+
+func TestDiffSchema(t *testing.T) {
+	b := NewBuildable(TestingDialect{})
+	from := []Table{
+		NewTable(
+			"PEOPLE",
+			&TableOption{Unique: [][]string{{"name", "age"}}},
+			IntColumn("id", &ColumnOption{PrimaryKey: true}),
+			StringColumn("name", &ColumnOption{Size: 64}),
+			IntColumn("age", nil),
+			StringColumn("nick", nil),
+		),
+		NewTable(
+			"GROUPS",
+			nil,
+			IntColumn("id", &ColumnOption{PrimaryKey: true}),
+			StringColumn("title", &ColumnOption{NotNull: true}),
+		),
+		NewTable("OLD", nil, IntColumn("id", nil)),
+	}
+	to := []Table{
+		NewTable(
+			"PEOPLE",
+			&TableOption{Unique: [][]string{{"id", "email"}}},
+			IntColumn("id", &ColumnOption{PrimaryKey: true}),
+			StringColumn("email", &ColumnOption{NotNull: true}),
+			StringColumn("name", &ColumnOption{Size: 64, NotNull: true}),
+			IntColumn("age", nil),
+		),
+		NewTable(
+			"TEAMS",
+			nil,
+			IntColumn("id", &ColumnOption{PrimaryKey: true}),
+			StringColumn("title", &ColumnOption{NotNull: true}),
+		),
+		NewTable("NEW", nil, IntColumn("id", nil), StringColumn("label", nil)),
+	}
+
+	diff, err := DiffSchema(b, from, to, &DiffOptions{DetectRenames: true})
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	changes := []string{
+		"create table NEW",
+		"add column PEOPLE.email",
+		"change column PEOPLE.name",
+		"drop column PEOPLE.nick",
+		"add unique PEOPLE (id, email)",
+		"rename table GROUPS to TEAMS",
+		"drop table OLD",
+	}
+	if len(diff.Changes) != len(changes) {
+		t.Fatalf("failed: changes %v", diff.Changes)
+	}
+	for idx, change := range diff.Changes {
+		if change.String() != changes[idx] {
+			t.Errorf("failed: change %d\nexpect: %s\ngot: %s", idx, changes[idx], change)
+		}
+	}
+	if len(diff.Unmigrated) != 1 || diff.Unmigrated[0].String() != "drop unique PEOPLE (name, age)" {
+		t.Errorf("failed: unmigrated %v", diff.Unmigrated)
+	}
+
+	queries := []string{
+		`CREATE TABLE "NEW" ( "id" INTEGER, "label" TEXT );`,
+		`ALTER TABLE "PEOPLE" ADD COLUMN "email" TEXT NOT NULL, CHANGE COLUMN "name" "name" TEXT NOT NULL, DROP COLUMN "nick";`,
+		`CREATE UNIQUE INDEX "uq_PEOPLE_id_email" ON "PEOPLE" ( "id", "email" );`,
+		`ALTER TABLE "GROUPS" RENAME TO "TEAMS";`,
+		`DROP TABLE "OLD";`,
+	}
+	if len(diff.Statements) != len(queries) {
+		t.Fatalf("failed: %d statements", len(diff.Statements))
+	}
+	for idx, stmt := range diff.Statements {
+		query, _, err := stmt.ToSql()
+		if err != nil {
+			t.Errorf("failed: statement %d: %s", idx, err)
+		} else if query != queries[idx] {
+			t.Errorf("failed: statement %d\nexpect: %s\ngot: %s", idx, queries[idx], query)
+		}
+	}
+
+	applied, err := diff.Apply(from)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	if !from[0].(*cTable).hasColumnName("nick") {
+		t.Errorf("failed: Apply modified the tables given")
+	}
+	converged, err := DiffSchema(b, applied, to, nil)
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	// only the unique constraint dropped remains
+	if len(converged.Changes) != 0 || len(converged.Unmigrated) != 1 {
+		t.Errorf("failed: not converged %v %v", converged.Changes, converged.Unmigrated)
+	}
+
+	if diff, err = DiffSchema(b, to, to, nil); err != nil {
+		t.Fatalf("failed: %s", err)
+	} else if !diff.Empty() {
+		t.Errorf("failed: not empty %v", diff.Changes)
+	}
+
+	// without DetectRenames, a renamed table is dropped and created
+	if diff, err = DiffSchema(b, from[1:2], to[1:2], nil); err != nil {
+		t.Fatalf("failed: %s", err)
+	} else if len(diff.Changes) != 2 || diff.Changes[0].String() != "create table TEAMS" || diff.Changes[1].String() != "drop table GROUPS" {
+		t.Errorf("failed: changes %v", diff.Changes)
+	}
+
+	// unique column options and constraints are compared as unique keys
+	if diff, err = DiffSchema(b, []Table{
+		NewTable("KEYS", &TableOption{Unique: [][]string{{"id"}}}, IntColumn("id", nil)),
+	}, []Table{
+		NewTable("KEYS", nil, IntColumn("id", &ColumnOption{Unique: true})),
+	}, nil); err != nil {
+		t.Fatalf("failed: %s", err)
+	} else if !diff.Empty() {
+		t.Errorf("failed: not empty %v", diff.Changes)
+	}
+
+	// the order of the columns of unique keys is ignored
+	if diff, err = DiffSchema(b, []Table{
+		NewTable("KEYS", &TableOption{Unique: [][]string{{"id", "code"}}}, IntColumn("id", nil), StringColumn("code", nil)),
+	}, []Table{
+		NewTable("KEYS", &TableOption{Unique: [][]string{{"code", "id"}}}, IntColumn("id", nil), StringColumn("code", nil)),
+	}, nil); err != nil {
+		t.Fatalf("failed: %s", err)
+	} else if !diff.Empty() {
+		t.Errorf("failed: not empty %v", diff.Changes)
+	}
+
+	// an added unique column is made unique by its index only
+	if diff, err = DiffSchema(b, []Table{
+		NewTable("KEYS", nil, IntColumn("id", nil)),
+	}, []Table{
+		NewTable("KEYS", nil, IntColumn("id", nil), StringColumn("code", &ColumnOption{Unique: true})),
+	}, nil); err != nil {
+		t.Fatalf("failed: %s", err)
+	} else if len(diff.Statements) != 2 {
+		t.Errorf("failed: %d statements", len(diff.Statements))
+	} else {
+		queries := []string{
+			`ALTER TABLE "KEYS" ADD COLUMN "code" TEXT;`,
+			`CREATE UNIQUE INDEX "uq_KEYS_code" ON "KEYS" ( "code" );`,
+		}
+		for idx, stmt := range diff.Statements {
+			if query, _, err := stmt.ToSql(); err != nil || query != queries[idx] {
+				t.Errorf("failed: statement %d\nexpect: %s\ngot: %s (%v)", idx, queries[idx], query, err)
+			}
+		}
+	}
+
+	joined := to[0].InnerJoin(to[1], to[0].C("id").Eq(to[1].C("id")))
+	if _, err = DiffSchema(b, from, []Table{joined}, nil); err == nil || err.Error() != "sqlbuilder: DiffSchema can use only natural tables." {
+		t.Errorf("failed: error %v", err)
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"strconv"
+)
+
+type cCommonTable struct {
+	name      string
+	anchor    *cSelect
+	recursive *cSelect
+	columns   []Column
+	err       error
+}
+
+// With returns a new common table expression named by the name and defined
+// by the q SELECT statement. The returned Table can be selected from or joined
+// in the same way as a natural table and any SELECT statement using it will
+// declare it within a "WITH" clause.
+func With(name string, q SelectBuilder) Table {
+	return newCommonTable(name, q)
+}
+
+// WithRecursive returns a new recursive common table expression named by the
+// name. The anchor SELECT statement defines the columns of the common table and
+// the recursive func is given the common table itself in order to construct the
+// recursive term, which is combined with the anchor by "UNION ALL".
+func WithRecursive(name string, anchor SelectBuilder, recursive func(self Table) SelectBuilder) Table {
+	c := newCommonTable(name, anchor)
+	if c.err != nil {
+		return c
+	}
+	if recursive == nil {
+		c.err = newError("recursive term is nil.")
+		return c
+	}
+	s, ok := recursive(c).(*cSelect)
+	if !ok || s == nil {
+		c.err = newError("recursive term is not a select statement.")
+		return c
+	}
+	if len(c.anchor.columns) != 0 && len(s.columns) != len(c.anchor.columns) {
+		c.err = newError("recursive term has %d columns, but anchor has %d.", len(s.columns), len(c.anchor.columns))
+		return c
+	}
+	c.recursive = s
+	return c
+}
+
+func newCommonTable(name string, q SelectBuilder) *cCommonTable {
+	c := &cCommonTable{
+		name: name,
+	}
+	if len(name) == 0 {
+		c.err = newError("name is empty.")
+		return c
+	}
+	s, ok := q.(*cSelect)
+	if !ok || s == nil {
+		c.err = newError("query is not a select statement.")
+		return c
+	}
+	if s.err != nil {
+		c.err = s.err
+		return c
+	}
+	c.anchor = s
+
+	for _, col := range s.resultColumns() {
+		if col == Star {
+			c.err = newError("common table can not select *, specify the columns.")
+			return c
+		}
+		typ := ColumnTypeAny
+		if cc := col.config(); cc != nil {
+			typ = cc.Type()
+		}
+		c.columns = append(c.columns, newColumnImplConfig(col.column_name(), typ, nil).toColumn(c))
+	}
+	return c
+}
+
+func (c *cCommonTable) serialize(b *builder) {
+	if c.err != nil {
+		b.SetError(c.err)
+		return
+	}
+	b.Append(b.dialect.QuoteField(c.name))
+}
+
+// serializeDefinition writes the "name AS ( ... )" form used within the WITH
+// clause of a statement
+func (c *cCommonTable) serializeDefinition(b *builder) {
+	if c.err != nil {
+		b.SetError(c.err)
+		return
+	}
+	b.Append(b.dialect.QuoteField(c.name))
+	b.Append(" AS ( ")
+	b.AppendItem(c.anchor)
+	if c.recursive != nil {
+		b.Append(" UNION ALL ")
+		b.AppendItem(c.recursive)
+	}
+	b.Append(" )")
+}
+
+func (c *cCommonTable) Name() string {
+	return c.name
+}
+
+func (c *cCommonTable) C(name string) Column {
+	for _, col := range c.columns {
+		if col.column_name() == name {
+			return col
+		}
+	}
+	return newErrorColumn(newError("column %s.%s was not found.", c.name, name))
+}
+
+func (c *cCommonTable) Columns() []Column {
+	return c.columns
+}
+
+func (c *cCommonTable) Option() *TableOption {
+	return nil
+}
+
+func (c *cCommonTable) InnerJoin(right Table, on Condition) Table {
+	return &cTableJoin{
+		left:  c,
+		right: right,
+		join:  gInnerJoin,
+		on:    on,
+	}
+}
+
+func (c *cCommonTable) LeftOuterJoin(right Table, on Condition) Table {
+	return &cTableJoin{
+		left:  c,
+		right: right,
+		join:  gLeftOuterJoin,
+		on:    on,
+	}
+}
+
+func (c *cCommonTable) RightOuterJoin(right Table, on Condition) Table {
+	return &cTableJoin{
+		left:  c,
+		right: right,
+		join:  gRightOuterJoin,
+		on:    on,
+	}
+}
+
+func (c *cCommonTable) FullOuterJoin(right Table, on Condition) Table {
+	return &cTableJoin{
+		left:  c,
+		right: right,
+		join:  gFullOuterJoin,
+		on:    on,
+	}
+}
+
+func (c *cCommonTable) hasColumn(trg Column) bool {
+	switch t := trg.(type) {
+	case *cColumnImpl:
+		if t == Star {
+			return true
+		}
+		for _, col := range c.columns {
+			if SameColumn(col, t) {
+				return true
+			}
+		}
+	case *cColumnAlias:
+		return c.hasColumn(t.column)
+	case iExpression:
+		for _, fncol := range t.columns() {
+			if !c.hasColumn(fncol) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (c *cCommonTable) Describe() (output string) {
+	output += strconv.Quote(c.name)
+	if c.anchor != nil {
+		output += " AS (" + c.anchor.Describe() + ")"
+	}
+	return
+}
+
+// collectCommonTables returns the list of common tables used by the given
+// table, ordered such that each common table is preceded by the common tables
+// it depends upon
+func collectCommonTables(t Table, seen map[*cCommonTable]bool, list []*cCommonTable) []*cCommonTable {
+	switch v := t.(type) {
+	case *cTableJoin:
+		list = collectCommonTables(v.left, seen, list)
+		list = collectCommonTables(v.right, seen, list)
+	case *cCommonTable:
+		if seen[v] || v.err != nil {
+			return list
+		}
+		seen[v] = true
+		list = collectCommonTables(v.anchor.from, seen, list)
+		if v.recursive != nil {
+			list = collectCommonTables(v.recursive.from, seen, list)
+		}
+		list = append(list, v)
+	}
+	return list
+}
+
+// serializeCommonTables writes the WITH clause declaring all common tables
+// used by the from Tables which have not already been declared by an outer
+// statement. It returns the common tables declared before the statement,
+// which the caller restores once the statement is written so that sibling
+// statements declare them again
+func serializeCommonTables(b *builder, from ...Table) map[*cCommonTable]bool {
+	var (
+		found     []*cCommonTable
+		declare   []*cCommonTable
+		recursive bool
+		seen      = make(map[*cCommonTable]bool)
+	)
+	for _, t := range from {
+		found = collectCommonTables(t, seen, found)
+	}
+	declared := b.commonTables
+	for _, ct := range found {
+		if declared[ct] {
+			continue
+		}
+		declare = append(declare, ct)
+		recursive = recursive || ct.recursive != nil
+	}
+	if len(declare) == 0 {
+		return declared
+	}
+
+	b.commonTables = make(map[*cCommonTable]bool, len(declared)+len(declare))
+	for ct := range declared {
+		b.commonTables[ct] = true
+	}
+	for _, ct := range declare {
+		b.commonTables[ct] = true
+	}
+
+	if recursive {
+		b.Append("WITH RECURSIVE ")
+	} else {
+		b.Append("WITH ")
+	}
+	for idx, ct := range declare {
+		if idx > 0 {
+			b.Append(", ")
+		}
+		ct.serializeDefinition(b)
+	}
+	b.Append(" ")
+	return declared
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompoundSelectBuilder is the Buildable interface wrapping of compound SELECT
+// statements, combining the results of two or more SELECT statements with one
+// of the UNION, UNION ALL, INTERSECT or EXCEPT operators
+type CompoundSelectBuilder interface {
+	// OrderBy sets the "ORDER BY" clause of the whole compound statement, the
+	// columns are referenced by name and must be present in the results of the
+	// first SELECT statement
+	OrderBy(desc bool, columns ...Column) CompoundSelectBuilder
+	// Limit sets the LIMIT clause of the whole compound statement
+	Limit(limit int) CompoundSelectBuilder
+	// Offset sets the OFFSET clause of the whole compound statement
+	Offset(offset int) CompoundSelectBuilder
+
+	ToSql() (query string, args []interface{}, err error)
+	ToSubquery(alias string) Table
+
+	// Describe returns a description of the compound statement
+	Describe() (output string)
+
+	serialize(b *builder)
+	privateCompoundSelect()
+}
+
+// cCompoundSelect represents a compound SELECT statement.
+type cCompoundSelect struct {
+	operator string
+	selects  []*cSelect
+	orderBy  []serializable
+	limit    int
+	offset   int
+
+	err error
+
+	dialect Dialect
+}
+
+type cCompoundOrderBy struct {
+	name string
+	desc bool
+}
+
+// Union returns a new compound statement combining the distinct results of
+// the selects given. The Dialect of the first SELECT statement is used when
+// generating the query.
+func Union(selects ...SelectBuilder) CompoundSelectBuilder {
+	return compoundSelect("UNION", selects)
+}
+
+// UnionAll returns a new compound statement combining all results, including
+// duplicates, of the selects given
+func UnionAll(selects ...SelectBuilder) CompoundSelectBuilder {
+	return compoundSelect("UNION ALL", selects)
+}
+
+// Intersect returns a new compound statement with the results present in all
+// of the selects given
+func Intersect(selects ...SelectBuilder) CompoundSelectBuilder {
+	return compoundSelect("INTERSECT", selects)
+}
+
+// Except returns a new compound statement with the results of the first select
+// which are not present in any of the other selects given
+func Except(selects ...SelectBuilder) CompoundSelectBuilder {
+	return compoundSelect("EXCEPT", selects)
+}
+
+func compoundSelect(operator string, selects []SelectBuilder) *cCompoundSelect {
+	c := &cCompoundSelect{
+		operator: operator,
+	}
+	if len(selects) < 2 {
+		c.err = newError("%s needs two or more select statements.", operator)
+		return c
+	}
+
+	var width int
+	for idx, sb := range selects {
+		s, ok := sb.(*cSelect)
+		if !ok || s == nil {
+			c.err = newError("%s got a nil select statement.", operator)
+			return c
+		}
+		if c.dialect == nil {
+			c.dialect = s.dialect
+		}
+		if s.err != nil {
+			c.err = s.err
+			return c
+		}
+		if s.orderBy != nil || s.limit != 0 || s.offset != 0 {
+			c.err = newError("%s select statements can not have ORDER BY, LIMIT or OFFSET clauses.", operator)
+			return c
+		}
+		if n := len(s.resultColumns()); idx == 0 {
+			width = n
+		} else if n != width {
+			c.err = newError("%s select statements have %d columns, but got %d.", operator, width, n)
+			return c
+		}
+		c.selects = append(c.selects, s)
+	}
+	return c
+}
+
+func (c *cCompoundSelect) privateCompoundSelect() {
+	// nop
+}
+
+// OrderBy sets "ORDER BY" clause. Use descending order if the desc is true, by the columns.
+func (c *cCompoundSelect) OrderBy(desc bool, columns ...Column) CompoundSelectBuilder {
+	if c.err != nil {
+		return c
+	}
+	for _, col := range columns {
+		if !c.hasResultColumn(col.column_name()) {
+			c.err = newError("column not found in %s: %q", c.operator, col.column_name())
+			return c
+		}
+		c.orderBy = append(c.orderBy, &cCompoundOrderBy{
+			name: col.column_name(),
+			desc: desc,
+		})
+	}
+	return c
+}
+
+// Limit sets LIMIT clause.
+func (c *cCompoundSelect) Limit(limit int) CompoundSelectBuilder {
+	if c.err != nil {
+		return c
+	}
+	c.limit = limit
+	return c
+}
+
+// Offset sets OFFSET clause.
+func (c *cCompoundSelect) Offset(offset int) CompoundSelectBuilder {
+	if c.err != nil {
+		return c
+	}
+	c.offset = offset
+	return c
+}
+
+func (c *cCompoundSelect) hasResultColumn(name string) bool {
+	for _, col := range c.selects[0].resultColumns() {
+		if col.column_name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *cCompoundSelect) selectColumns() []Column {
+	if len(c.selects) == 0 {
+		return nil
+	}
+	return c.selects[0].selectColumns()
+}
+
+func (c *cCompoundSelect) resultColumns() []Column {
+	if len(c.selects) == 0 {
+		return nil
+	}
+	return c.selects[0].resultColumns()
+}
+
+func (c *cCompoundSelect) serialize(b *builder) {
+	if c.err != nil {
+		b.SetError(c.err)
+		return
+	}
+	if !b.dialect.SupportsCompound(c.operator) {
+		b.SetError(newError("%s is not supported by the %s dialect.", c.operator, b.dialect.Name()))
+		return
+	}
+
+	// WITH
+	from := make([]Table, len(c.selects))
+	for idx, s := range c.selects {
+		from[idx] = s.from
+	}
+	commonTables := serializeCommonTables(b, from...)
+	defer func() {
+		b.commonTables = commonTables
+	}()
+
+	// SELECT ... OPERATOR SELECT ...
+	for idx, s := range c.selects {
+		if idx > 0 {
+			b.Append(" " + c.operator + " ")
+		}
+		b.AppendItem(s)
+	}
+
+	// ORDER BY
+	if c.orderBy != nil {
+		b.Append(" ORDER BY ")
+		b.AppendItems(c.orderBy, ", ")
+	}
+
+	// LIMIT
+	if c.limit != 0 {
+		b.Append(" LIMIT ")
+		b.AppendValue(c.limit)
+	}
+
+	// OFFSET
+	if c.offset != 0 {
+		b.Append(" OFFSET ")
+		b.AppendValue(c.offset)
+	}
+}
+
+// ToSql generates query string, placeholder arguments, and returns err on errors.
+func (c *cCompoundSelect) ToSql() (query string, args []interface{}, err error) {
+	b := newBuilder(c.dialect)
+	b.AppendItem(c)
+	return b.Query(), b.Args(), b.Err()
+}
+
+func (c *cCompoundSelect) ToSubquery(alias string) Table {
+	return newSubQuery(c, alias)
+}
+
+func (c *cCompoundSelect) Describe() (output string) {
+	var parts []string
+	for _, s := range c.selects {
+		parts = append(parts, s.Describe())
+	}
+	return strings.Join(parts, "\n"+c.operator+"\n")
+}
+
+func (c *cCompoundOrderBy) serialize(b *builder) {
+	b.Append(b.dialect.QuoteField(c.name))
+	if c.desc {
+		b.Append(" DESC")
+	} else {
+		b.Append(" ASC")
+	}
+}
+
+func (c *cCompoundOrderBy) Describe() (output string) {
+	dir := "ASC"
+	if c.desc {
+		dir = "DESC"
+	}
+	return "ORDER BY " + strconv.Quote(c.name) + " " + strconv.Quote(dir)
+}
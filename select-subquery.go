@@ -25,13 +25,20 @@ import (
 	"strconv"
 )
 
+// selectStatement is implemented by the statements which can be used as a
+// subquery
+type selectStatement interface {
+	serializable
+	selectColumns() []Column
+}
+
 type cSubQuery struct {
-	stat  *cSelect
+	stat  selectStatement
 	alias string
 	err   error
 }
 
-func newSubQuery(s *cSelect, alias string) *cSubQuery {
+func newSubQuery(s selectStatement, alias string) *cSubQuery {
 	m := &cSubQuery{
 		stat:  s,
 		alias: alias,
@@ -59,22 +66,26 @@ func (c *cSubQuery) Name() string {
 }
 
 func (c *cSubQuery) C(name string) Column {
-	for _, col := range c.stat.columns {
-		if ac, ok := col.(iAliasedColumn); ok {
-			if ac.column_alias() == name {
-				return col.config().toColumn(c)
-			}
+	for _, col := range c.stat.selectColumns() {
+		if col.column_name() != name {
+			continue
+		}
+		// aliases and functions are named by the alias in the results
+		cc := col.config()
+		if cc == nil {
+			return newColumnImplConfig(name, ColumnTypeAny, nil).toColumn(c)
 		}
-		if col.column_name() == name {
-			return col.config().toColumn(c)
+		if cc.Name() != name {
+			return newColumnImplConfig(name, cc.Type(), cc.Option()).toColumn(c)
 		}
+		return cc.toColumn(c)
 	}
 	return newErrorColumn(newError("column %s was not found.", name))
 }
 
 func (c *cSubQuery) Columns() []Column {
-	l := make([]Column, len(c.stat.columns))
-	for _, col := range c.stat.columns {
+	l := make([]Column, len(c.stat.selectColumns()))
+	for _, col := range c.stat.selectColumns() {
 		if _, ok := col.(iAliasedColumn); ok {
 			l = append(l, col.config().toColumn(c))
 		}
@@ -115,7 +126,7 @@ func (c *cSubQuery) hasColumn(trg Column) bool {
 		if cimpl.table != c {
 			return false
 		}
-		for _, col := range c.stat.columns {
+		for _, col := range c.stat.selectColumns() {
 			if col.column_name() == trg.column_name() {
 				return true
 			}
@@ -123,20 +134,29 @@ func (c *cSubQuery) hasColumn(trg Column) bool {
 		return false
 	}
 	if acol, ok := trg.(*cColumnAlias); ok {
-		if acol.column.(*cColumnImpl).table != c {
+		if cimpl, ok := acol.column.(*cColumnImpl); !ok || cimpl.table != c {
 			return false
 		}
-		for _, col := range c.stat.columns {
+		for _, col := range c.stat.selectColumns() {
 			if col.column_name() == trg.column_name() {
 				return true
 			}
 		}
 		return false
 	}
-	if sqlfn, ok := trg.(*cSqlFunc); ok {
-		for _, fncol := range sqlfn.columns() {
+	if expr, ok := trg.(iExpression); ok {
+		for _, fncol := range expr.columns() {
+			if fncol == Star {
+				continue
+			}
+			if _, ok := fncol.(iExpression); ok {
+				if !c.hasColumn(fncol) {
+					return false
+				}
+				continue
+			}
 			find := false
-			for _, col := range c.stat.columns {
+			for _, col := range c.stat.selectColumns() {
 				if col.column_name() == fncol.column_name() {
 					find = true
 				}
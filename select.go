@@ -32,6 +32,9 @@ type SelectBuilder interface {
 	Having(cond Condition) SelectBuilder
 
 	GroupBy(columns ...Column) SelectBuilder
+	// Window adds a window named by the name to the "WINDOW" clause, which
+	// can be used by the window functions (see SqlFunc.OverWindow)
+	Window(name string, window WindowSpec) SelectBuilder
 	OrderBy(desc bool, columns ...Column) SelectBuilder
 	Limit(limit int) SelectBuilder
 	Offset(offset int) SelectBuilder
@@ -59,6 +62,11 @@ type cSelect struct {
 	limit    int
 	offset   int
 	having   Condition
+	windows  []serializable
+
+	// outer are the columns of WHERE clause not found in FROM, allowed only
+	// when the statement is a correlated subquery of a condition
+	outer []Column
 
 	err error
 
@@ -107,14 +115,16 @@ func (s *cSelect) Columns(columns ...Column) SelectBuilder {
 }
 
 // Where sets WHERE clause.  The cond is filter condition.
+// The columns not found in FROM are only allowed when the statement is used
+// as a correlated subquery (see Exists), referencing the outer statement.
 func (s *cSelect) Where(cond Condition) SelectBuilder {
 	if s.err != nil {
 		return s
 	}
+	s.outer = nil
 	for _, col := range cond.columns() {
 		if !s.from.hasColumn(col) {
-			s.err = newError("column not found in FROM: %q", col.column_name())
-			return s
+			s.outer = append(s.outer, col)
 		}
 	}
 
@@ -153,6 +163,34 @@ func (s *cSelect) Having(cond Condition) SelectBuilder {
 	return s
 }
 
+// Window adds the window named by the name to "WINDOW" clause.
+func (s *cSelect) Window(name string, window WindowSpec) SelectBuilder {
+	if s.err != nil {
+		return s
+	}
+	if len(name) == 0 {
+		s.err = newError("window name is empty.")
+		return s
+	}
+	if window == nil {
+		window = Window()
+	}
+	for _, col := range window.columns() {
+		if !s.from.hasColumn(col) {
+			s.err = newError("column not found in FROM: %q", col.column_name())
+			return s
+		}
+	}
+	for _, w := range s.windows {
+		if w.(*cNamedWindow).name == name {
+			s.err = newError("window %s is already defined.", name)
+			return s
+		}
+	}
+	s.windows = append(s.windows, &cNamedWindow{name: name, window: window})
+	return s
+}
+
 // OrderBy sets "ORDER BY" clause. Use descending order if the desc is true, by the columns.
 func (s *cSelect) OrderBy(desc bool, columns ...Column) SelectBuilder {
 	if s.err != nil {
@@ -187,10 +225,33 @@ func (s *cSelect) Offset(offset int) SelectBuilder {
 }
 
 func (s *cSelect) serialize(b *builder) {
+	if s.err == nil && len(s.outer) > 0 {
+		b.SetError(newError("column not found in FROM: %q", s.outer[0].column_name()))
+		return
+	}
+	s.serializeSelect(b)
+}
+
+// serializeSelect writes the statement, allowing columns of outer tables
+func (s *cSelect) serializeSelect(b *builder) {
 	if s.err != nil {
 		b.SetError(s.err)
 		return
 	}
+	windows := b.windows
+	b.windows = make(map[string]bool, len(s.windows))
+	for _, w := range s.windows {
+		b.windows[w.(*cNamedWindow).name] = true
+	}
+	defer func() {
+		b.windows = windows
+	}()
+
+	// WITH
+	commonTables := serializeCommonTables(b, s.from)
+	defer func() {
+		b.commonTables = commonTables
+	}()
 
 	// SELECT COLUMN
 	b.Append("SELECT ")
@@ -224,6 +285,12 @@ func (s *cSelect) serialize(b *builder) {
 		b.AppendItem(s.having)
 	}
 
+	// WINDOW
+	if s.windows != nil {
+		b.Append(" WINDOW ")
+		b.AppendItems(s.windows, ", ")
+	}
+
 	// ORDER BY
 	if s.orderBy != nil {
 		b.Append(" ORDER BY ")
@@ -251,6 +318,19 @@ func (s *cSelect) ToSql() (query string, args []interface{}, err error) {
 	return b.Query(), b.Args(), b.Err()
 }
 
+// resultColumns returns the columns present in the results of the statement,
+// which are the columns of the FROM table when none were specified
+func (s *cSelect) resultColumns() []Column {
+	if len(s.columns) == 0 || (len(s.columns) == 1 && s.columns[0] == Star) {
+		return s.from.Columns()
+	}
+	return s.columns
+}
+
+func (s *cSelect) selectColumns() []Column {
+	return s.columns
+}
+
 func (s *cSelect) ToSubquery(alias string) Table {
 	return newSubQuery(s, alias)
 }
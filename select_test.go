@@ -151,6 +151,206 @@ func TestSubquery(t *testing.T) {
 	}
 }
 
+func TestCommonTable(t *testing.T) {
+	table1 := NewTable(
+		"TABLE_A",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		IntColumn("test1", nil),
+		IntColumn("test2", nil),
+	)
+	nodes := NewTable(
+		"NODES",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		IntColumn("parent_id", nil),
+	)
+
+	cte := With("CTE_A", Select(table1).
+		Columns(table1.C("id"), table1.C("test1").As("t1")).
+		Where(table1.C("test2").Gt(10)))
+	tree := WithRecursive("TREE",
+		Select(nodes).
+			Columns(nodes.C("id"), nodes.C("parent_id")).
+			Where(nodes.C("parent_id").Eq(nil)),
+		func(self Table) SelectBuilder {
+			return Select(nodes.InnerJoin(self, nodes.C("parent_id").Eq(self.C("id")))).
+				Columns(nodes.C("id"), nodes.C("parent_id"))
+		},
+	)
+	depends := With("CTE_B", Select(cte).Columns(cte.C("t1")).Where(cte.C("id").Lt(5)))
+
+	var cases = []statementTestCase{{
+		stmt: Select(cte).
+			Columns(cte.C("id"), cte.C("t1")).
+			Where(cte.C("id").Eq(1)),
+		query: `WITH "CTE_A" AS ( SELECT "TABLE_A"."id", "TABLE_A"."test1" AS "t1" FROM "TABLE_A" WHERE "TABLE_A"."test2">? ) ` +
+			`SELECT "CTE_A"."id", "CTE_A"."t1" FROM "CTE_A" WHERE "CTE_A"."id"=?;`,
+		args:   []interface{}{int64(10), int64(1)},
+		errmsg: "",
+	}, {
+		stmt: Select(table1.InnerJoin(cte, table1.C("id").Eq(cte.C("id")))).
+			Columns(table1.C("test2"), cte.C("t1")),
+		query: `WITH "CTE_A" AS ( SELECT "TABLE_A"."id", "TABLE_A"."test1" AS "t1" FROM "TABLE_A" WHERE "TABLE_A"."test2">? ) ` +
+			`SELECT "TABLE_A"."test2", "CTE_A"."t1" FROM "TABLE_A" INNER JOIN "CTE_A" ON "TABLE_A"."id"="CTE_A"."id";`,
+		args:   []interface{}{int64(10)},
+		errmsg: "",
+	}, {
+		stmt: Select(tree).Columns(tree.C("id")),
+		query: `WITH RECURSIVE "TREE" AS ( ` +
+			`SELECT "NODES"."id", "NODES"."parent_id" FROM "NODES" WHERE "NODES"."parent_id" IS NULL ` +
+			`UNION ALL ` +
+			`SELECT "NODES"."id", "NODES"."parent_id" FROM "NODES" INNER JOIN "TREE" ON "NODES"."parent_id"="TREE"."id" ` +
+			`) SELECT "TREE"."id" FROM "TREE";`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt: Select(depends),
+		query: `WITH "CTE_A" AS ( SELECT "TABLE_A"."id", "TABLE_A"."test1" AS "t1" FROM "TABLE_A" WHERE "TABLE_A"."test2">? ), ` +
+			`"CTE_B" AS ( SELECT "CTE_A"."t1" FROM "CTE_A" WHERE "CTE_A"."id"<? ) ` +
+			`SELECT * FROM "CTE_B";`,
+		args:   []interface{}{int64(10), int64(5)},
+		errmsg: "",
+	}, {
+		stmt: Select(table1).Columns(table1.C("id")).Where(And(
+			Exists(Select(cte).Where(cte.C("id").Eq(table1.C("id")))),
+			Exists(Select(cte).Where(cte.C("t1").Eq(table1.C("test1")))),
+		)),
+		query: `SELECT "TABLE_A"."id" FROM "TABLE_A" WHERE ` +
+			`EXISTS ( WITH "CTE_A" AS ( SELECT "TABLE_A"."id", "TABLE_A"."test1" AS "t1" FROM "TABLE_A" WHERE "TABLE_A"."test2">? ) ` +
+			`SELECT * FROM "CTE_A" WHERE "CTE_A"."id"="TABLE_A"."id" ) AND ` +
+			`EXISTS ( WITH "CTE_A" AS ( SELECT "TABLE_A"."id", "TABLE_A"."test1" AS "t1" FROM "TABLE_A" WHERE "TABLE_A"."test2">? ) ` +
+			`SELECT * FROM "CTE_A" WHERE "CTE_A"."t1"="TABLE_A"."test1" );`,
+		args:   []interface{}{int64(10), int64(10)},
+		errmsg: "",
+	}, {
+		stmt:   Select(cte).Columns(cte.C("test2")),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: column not found in FROM: \"\"",
+	}, {
+		stmt:   Select(With("", Select(table1))),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: name is empty.",
+	}, {
+		stmt: Select(WithRecursive("TREE", Select(nodes).Columns(nodes.C("id")), func(self Table) SelectBuilder {
+			return Select(nodes).Columns(nodes.C("id"), nodes.C("parent_id"))
+		})),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: recursive term has 2 columns, but anchor has 1.",
+	}}
+
+	for num, c := range cases {
+		mes, args, ok := c.Run()
+		if !ok {
+			t.Errorf(mes+" (case no.%d)", append(args, num)...)
+		}
+	}
+}
+
+func TestCompoundSelect(t *testing.T) {
+	table1 := NewTable(
+		"TABLE_A",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		IntColumn("test1", nil),
+		IntColumn("test2", nil),
+	)
+	table2 := NewTable(
+		"TABLE_B",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		IntColumn("test1", nil),
+	)
+	sel1 := Select(table1).Columns(table1.C("id"), table1.C("test1")).Where(table1.C("test2").Eq(1))
+	sel2 := Select(table2).Columns(table2.C("id"), table2.C("test1")).Where(table2.C("test1").Gt(2))
+
+	var cases = []statementTestCase{{
+		stmt: Union(sel1, sel2),
+		query: `SELECT "TABLE_A"."id", "TABLE_A"."test1" FROM "TABLE_A" WHERE "TABLE_A"."test2"=? ` +
+			`UNION SELECT "TABLE_B"."id", "TABLE_B"."test1" FROM "TABLE_B" WHERE "TABLE_B"."test1">?;`,
+		args:   []interface{}{int64(1), int64(2)},
+		errmsg: "",
+	}, {
+		stmt: UnionAll(sel1, sel2).
+			OrderBy(true, table1.C("test1")).
+			Limit(10).
+			Offset(20),
+		query: `SELECT "TABLE_A"."id", "TABLE_A"."test1" FROM "TABLE_A" WHERE "TABLE_A"."test2"=? ` +
+			`UNION ALL SELECT "TABLE_B"."id", "TABLE_B"."test1" FROM "TABLE_B" WHERE "TABLE_B"."test1">? ` +
+			`ORDER BY "test1" DESC LIMIT ? OFFSET ?;`,
+		args:   []interface{}{int64(1), int64(2), 10, 20},
+		errmsg: "",
+	}, {
+		stmt: Intersect(Select(table1).Columns(table1.C("id")), Select(table2).Columns(table2.C("id"))),
+		query: `SELECT "TABLE_A"."id" FROM "TABLE_A" ` +
+			`INTERSECT SELECT "TABLE_B"."id" FROM "TABLE_B";`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt: Except(Select(table1).Columns(table1.C("id").As("key")), Select(table2).Columns(table2.C("id"))).
+			OrderBy(false, table1.C("id").As("key")),
+		query: `SELECT "TABLE_A"."id" AS "key" FROM "TABLE_A" ` +
+			`EXCEPT SELECT "TABLE_B"."id" FROM "TABLE_B" ORDER BY "key" ASC;`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt:   Union(sel1),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: UNION needs two or more select statements.",
+	}, {
+		stmt:   Union(sel1, Select(table1)),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: UNION select statements have 2 columns, but got 3.",
+	}, {
+		stmt:   Union(sel1, Select(table2).Columns(table2.C("id"), table2.C("test1")).Limit(1)),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: UNION select statements can not have ORDER BY, LIMIT or OFFSET clauses.",
+	}, {
+		stmt:   Union(sel1, sel2).OrderBy(false, table1.C("test2")),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: column not found in UNION: \"test2\"",
+	}}
+
+	for num, c := range cases {
+		mes, args, ok := c.Run()
+		if !ok {
+			t.Errorf(mes+" (case no.%d)", append(args, num)...)
+		}
+	}
+
+	subquery := Union(sel1, sel2).ToSubquery("SQ1")
+	query, attrs, err := Select(subquery).
+		Columns(subquery.C("id")).
+		Where(subquery.C("test1").Eq(3)).ToSql()
+	if err != nil {
+		t.Errorf("failed \ngot %#v", err)
+	}
+	if `SELECT "SQ1"."id" FROM ( `+
+		`SELECT "TABLE_A"."id", "TABLE_A"."test1" FROM "TABLE_A" WHERE "TABLE_A"."test2"=? `+
+		`UNION SELECT "TABLE_B"."id", "TABLE_B"."test1" FROM "TABLE_B" WHERE "TABLE_B"."test1">? `+
+		`) AS SQ1 WHERE "SQ1"."test1"=?;` != query {
+		t.Errorf("failed \ngot %s", query)
+	}
+	if !reflect.DeepEqual([]interface{}{int64(1), int64(2), int64(3)}, attrs) {
+		t.Errorf("failed \ngot %#v", attrs)
+	}
+}
+
 func BenchmarkSelect(b *testing.B) {
 	table1 := NewTable(
 		"TABLE_A",
@@ -22,6 +22,7 @@
 package sqlbuilder
 
 import (
+	"strings"
 	"sync"
 )
 
@@ -37,9 +38,41 @@ type Buildable interface {
 
 	// NewTable wraps the NewTable package-level function and for all named
 	// tables, stores the Table reference within the Buildable instance for
-	// later retrieval with the T method
+	// later retrieval with the T method. Like NewTable, panics when the name
+	// is already used by another table
 	NewTable(name string, option *TableOption, column_configs ...ColumnConfig) (t Table)
 
+	// NewTableFromStruct wraps the NewTableFromStruct package-level function
+	// in the same way as NewTable, returning an error when the name is
+	// already used by another table
+	NewTableFromStruct(name string, structure interface{}) (t Table, err error)
+
+	// T returns the table named, or nil
+	T(name string) Table
+
+	// SetTable stores the table given, replacing the one of the same name if
+	// any, and reports whether it was stored
+	SetTable(t Table) (ok bool)
+
+	// ListTables returns the names of the tables stored, in the order they
+	// were first stored
+	ListTables() (names []string)
+
+	// Tables returns the tables stored, in the order they were first stored
+	Tables() (tables []Table)
+
+	// SortedTables returns the tables stored, ordered so that the tables
+	// referenced by foreign keys come before the tables referencing them
+	SortedTables() (tables []Table, err error)
+
+	// CreateAll starts new CREATE TABLE statement builders for all tables
+	// stored, in the order of SortedTables
+	CreateAll() (builders []CreateTableBuilder, err error)
+
+	// DropAll starts new DROP TABLE statement builders for all tables
+	// stored, in the reverse order of SortedTables
+	DropAll() (builders []DropTableBuilder, err error)
+
 	// AlterTable starts a new ALTER TABLE statement builder
 	AlterTable(tbl Table) AlterTableBuilder
 
@@ -52,17 +85,23 @@ type Buildable interface {
 	// Delete starts a new DELETE statement builder
 	Delete(from Table) DeleteBuilder
 
+	// DropTable starts a new DROP TABLE statement builder
+	DropTable(tbl Table) DropTableBuilder
+
 	// Insert starts a new INSERT statement builder
 	Insert(into Table) InsertBuilder
 
 	// Select starts a new SELECT statement builder
 	Select(from Table) SelectBuilder
+
+	// Update starts a new UPDATE statement builder
+	Update(tbl Table) UpdateBuilder
 }
 
 type buildable struct {
 	dialect Dialect
-	//tables  map[string]Table
-	//order   []string
+	tables  map[string]Table
+	order   []string
 
 	m *sync.RWMutex
 }
@@ -71,15 +110,133 @@ type buildable struct {
 func NewBuildable(d Dialect) Buildable {
 	b := &buildable{
 		dialect: d,
-		//tables:  make(map[string]Table),
-		m: &sync.RWMutex{},
+		tables:  make(map[string]Table),
+		m:       &sync.RWMutex{},
 	}
 	return b
 }
 
 func (b *buildable) NewTable(name string, option *TableOption, column_configs ...ColumnConfig) (t Table) {
 	t = NewTable(name, option, column_configs...)
-	//b.trackTable(t)
+	if err := b.trackTable(t); err != nil {
+		panic(err)
+	}
+	return
+}
+
+func (b *buildable) NewTableFromStruct(name string, structure interface{}) (t Table, err error) {
+	if t, err = NewTableFromStruct(name, structure); err == nil {
+		if err = b.trackTable(t); err != nil {
+			t = nil
+		}
+	}
+	return
+}
+
+// trackTable stores the table given, unless the name is already used
+func (b *buildable) trackTable(t Table) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+	name := t.Name()
+	if _, present := b.tables[name]; present {
+		return newError("table %s is already defined.", name)
+	}
+	b.tables[name] = t
+	b.order = append(b.order, name)
+	return nil
+}
+
+func (b *buildable) T(name string) Table {
+	b.m.RLock()
+	defer b.m.RUnlock()
+	return b.tables[name]
+}
+
+func (b *buildable) SetTable(t Table) (ok bool) {
+	if t == nil || t.Name() == "" {
+		return false
+	}
+	b.m.Lock()
+	defer b.m.Unlock()
+	name := t.Name()
+	if _, present := b.tables[name]; !present {
+		b.order = append(b.order, name)
+	}
+	b.tables[name] = t
+	return true
+}
+
+func (b *buildable) ListTables() (names []string) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+	return append(names, b.order...)
+}
+
+func (b *buildable) Tables() (tables []Table) {
+	b.m.RLock()
+	defer b.m.RUnlock()
+	for _, name := range b.order {
+		tables = append(tables, b.tables[name])
+	}
+	return
+}
+
+func (b *buildable) SortedTables() (tables []Table, err error) {
+	declared := b.Tables()
+	// visiting marks the tables being sorted, to detect cycles
+	visiting := make(map[string]bool)
+	sorted := make(map[string]bool)
+	var visit func(t Table, path []string) error
+	visit = func(t Table, path []string) error {
+		name := t.Name()
+		if sorted[name] {
+			return nil
+		} else if visiting[name] {
+			return newError("foreign keys of tables %s form a cycle.", strings.Join(append(path, name), ", "))
+		}
+		visiting[name] = true
+		if option := t.Option(); option != nil {
+			for _, fk := range option.ForeignKeys {
+				// self references and unknown tables do not order the tables
+				if ref := b.T(fk.RefTable); ref != nil && fk.RefTable != name {
+					if err := visit(ref, append(path, name)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		visiting[name] = false
+		sorted[name] = true
+		tables = append(tables, t)
+		return nil
+	}
+	for _, t := range declared {
+		if err = visit(t, nil); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func (b *buildable) CreateAll() (builders []CreateTableBuilder, err error) {
+	tables, err := b.SortedTables()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tables {
+		builders = append(builders, createTable(t, b.Dialect()))
+	}
+	return
+}
+
+func (b *buildable) DropAll() (builders []DropTableBuilder, err error) {
+	tables, err := b.SortedTables()
+	if err != nil {
+		return nil, err
+	}
+	for idx := len(tables) - 1; idx >= 0; idx-- {
+		builders = append(builders, dropTable(tables[idx], b.Dialect()))
+	}
 	return
 }
 
@@ -105,6 +262,10 @@ func (b *buildable) Delete(from Table) DeleteBuilder {
 	return deleteFn(from, b.Dialect())
 }
 
+func (b *buildable) DropTable(tbl Table) DropTableBuilder {
+	return dropTable(tbl, b.Dialect())
+}
+
 func (b *buildable) Insert(into Table) InsertBuilder {
 	return insert(into, b.Dialect())
 }
@@ -112,3 +273,7 @@ func (b *buildable) Insert(into Table) InsertBuilder {
 func (b *buildable) Select(from Table) SelectBuilder {
 	return selectFn(from, b.Dialect())
 }
+
+func (b *buildable) Update(tbl Table) UpdateBuilder {
+	return update(tbl, b.Dialect())
+}
@@ -45,21 +45,77 @@ func TestBuildable(t *testing.T) {
 
 		So(b.Dialect(), ShouldEqual, d)
 
-		//tb2 := b.NewTable("TABLE_2", &TableOption{}, IntColumn("id", &ColumnOption{PrimaryKey: true}))
-		//So(tb2, ShouldNotBeNil)
-		//So(tb2.Name(), ShouldEqual, "TABLE_2")
-		//So(b.T("TABLE_2"), ShouldEqual, tb2)
-		//So(b.T("NOPE"), ShouldBeNil)
+		tb2 := b.NewTable("TABLE_2", &TableOption{}, IntColumn("id", &ColumnOption{PrimaryKey: true}))
+		So(tb2, ShouldNotBeNil)
+		So(tb2.Name(), ShouldEqual, "TABLE_2")
+		So(b.T("TABLE_2"), ShouldEqual, tb2)
+		So(b.T("NOPE"), ShouldBeNil)
 
-		//tb2a := NewTable("TABLE_2", &TableOption{}, IntColumn("id", &ColumnOption{PrimaryKey: true}), StringColumn("data", &ColumnOption{}))
-		//So(tb2a, ShouldNotBeNil)
-		//So(tb2a.Name(), ShouldEqual, "TABLE_2")
-		//So(b.T("TABLE_2"), ShouldNotEqual, tb2a)
-		//So(b.SetTable(tb2a), ShouldBeTrue)
-		//So(b.T("TABLE_2"), ShouldEqual, tb2a)
+		tb2a := NewTable("TABLE_2", &TableOption{}, IntColumn("id", &ColumnOption{PrimaryKey: true}), StringColumn("data", &ColumnOption{}))
+		So(tb2a, ShouldNotBeNil)
+		So(tb2a.Name(), ShouldEqual, "TABLE_2")
+		So(b.T("TABLE_2"), ShouldNotEqual, tb2a)
+		So(b.SetTable(tb2a), ShouldBeTrue)
+		So(b.T("TABLE_2"), ShouldEqual, tb2a)
+		So(b.SetTable(nil), ShouldBeFalse)
 
-		//So(b.ListTables(), ShouldEqual, []string{"TABLE_2"})
+		So(b.ListTables(), ShouldEqual, []string{"TABLE_2"})
+		So(b.Tables(), ShouldResemble, []Table{tb2a})
 
+		So(func() {
+			b.NewTable("TABLE_2", &TableOption{}, IntColumn("id", nil))
+		}, ShouldPanicWith, newError("table %s is already defined.", "TABLE_2"))
+		_, err := b.NewTableFromStruct("TABLE_2", struct {
+			Id int64 `sql:"id"`
+		}{})
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, "sqlbuilder: table TABLE_2 is already defined.")
+	})
+
+	Convey("CreateAll", t, func() {
+		b := NewBuildable(d)
+		members := b.NewTable("MEMBERS", &TableOption{
+			ForeignKeys: []ForeignKey{
+				{Columns: []string{"person_id"}, RefTable: "PEOPLE", RefColumns: []string{"id"}},
+				{Columns: []string{"group_id"}, RefTable: "GROUPS", RefColumns: []string{"id"}},
+			},
+		}, IntColumn("person_id", nil), IntColumn("group_id", nil))
+		groups := b.NewTable("GROUPS", &TableOption{
+			ForeignKeys: []ForeignKey{
+				{Columns: []string{"parent_id"}, RefTable: "GROUPS", RefColumns: []string{"id"}},
+			},
+		}, IntColumn("id", &ColumnOption{PrimaryKey: true}), IntColumn("parent_id", nil))
+		people := b.NewTable("PEOPLE", nil, IntColumn("id", &ColumnOption{PrimaryKey: true}))
+
+		So(b.ListTables(), ShouldEqual, []string{"MEMBERS", "GROUPS", "PEOPLE"})
+		sorted, err := b.SortedTables()
+		So(err, ShouldBeNil)
+		So(sorted, ShouldResemble, []Table{people, groups, members})
+
+		creates, err := b.CreateAll()
+		So(err, ShouldBeNil)
+		So(len(creates), ShouldEqual, 3)
+		query, _, err := creates[0].ToSql()
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, `CREATE TABLE "PEOPLE" ( "id" INTEGER PRIMARY KEY );`)
+
+		drops, err := b.DropAll()
+		So(err, ShouldBeNil)
+		So(len(drops), ShouldEqual, 3)
+		query, _, err = drops[0].ToSql()
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, `DROP TABLE "MEMBERS";`)
+
+		b.SetTable(NewTable("PEOPLE", &TableOption{
+			ForeignKeys: []ForeignKey{
+				{Columns: []string{"id"}, RefTable: "MEMBERS", RefColumns: []string{"person_id"}},
+			},
+		}, IntColumn("id", &ColumnOption{PrimaryKey: true})))
+		_, err = b.CreateAll()
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, "sqlbuilder: foreign keys of tables MEMBERS, PEOPLE, MEMBERS form a cycle.")
+		_, err = b.DropAll()
+		So(err, ShouldNotBeNil)
 	})
 
 	Convey("AlterTable", t, func() {
@@ -108,6 +164,17 @@ func TestBuildable(t *testing.T) {
 		So(sql, ShouldEqual, `DELETE FROM "TABLE_A";`)
 	})
 
+	Convey("DropTable", t, func() {
+		So(b, ShouldNotBeNil)
+
+		sb := b.DropTable(tbl)
+		So(sb, ShouldNotBeNil)
+		sql, argv, err := sb.ToSql()
+		So(err, ShouldBeNil)
+		So(argv, ShouldBeEmpty)
+		So(sql, ShouldEqual, `DROP TABLE "TABLE_A";`)
+	})
+
 	Convey("Insert", t, func() {
 		So(b, ShouldNotBeNil)
 
@@ -135,6 +202,17 @@ func TestBuildable(t *testing.T) {
 
 	})
 
+	Convey("Update", t, func() {
+		So(b, ShouldNotBeNil)
+
+		sb := b.Update(tbl).Set(tbl.C("count"), 10)
+		So(sb, ShouldNotBeNil)
+		sql, argv, err := sb.ToSql()
+		So(err, ShouldBeNil)
+		So(argv, ShouldEqual, []interface{}{int64(10)})
+		So(sql, ShouldEqual, `UPDATE "TABLE_A" SET "count"=?;`)
+	})
+
 	Convey("Columns", t, func() {
 		table := NewTable(
 			"TABLE_A",
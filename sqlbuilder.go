@@ -40,6 +40,21 @@ type builder struct {
 	err   error
 
 	dialect Dialect
+
+	// commonTables tracks the common tables already declared by a WITH clause
+	commonTables map[*cCommonTable]bool
+
+	// inline writes values as SQL literals and columns without their table
+	// name, for DDL statements which can not take bind variables
+	inline bool
+
+	// windows are the names of the WINDOW clause of the SELECT statement
+	// being written
+	windows map[string]bool
+
+	// renames maps column names to the names written in inline mode, for the
+	// check conditions of renamed columns
+	renames map[string]string
 }
 
 func newBuilder(d Dialect) *builder {
@@ -51,6 +66,8 @@ func newBuilder(d Dialect) *builder {
 		args:    make([]interface{}, 0, 8),
 		err:     nil,
 		dialect: d,
+
+		commonTables: make(map[*cCommonTable]bool),
 	}
 }
 
@@ -96,6 +113,16 @@ func (b *builder) AppendValue(val interface{}) {
 		return
 	}
 
+	if b.inline {
+		str, err := sqlLiteral(b.dialect, val)
+		if err != nil {
+			b.SetError(err)
+			return
+		}
+		b.query.WriteString(str)
+		return
+	}
+
 	b.query.WriteString(b.dialect.BindVar(len(b.args) + 1))
 	b.args = append(b.args, val)
 	return
@@ -124,3 +151,17 @@ func (b *builder) AppendItem(part serializable) {
 	}
 	part.serialize(b)
 }
+
+// Render returns the query of the part without appending it. The values of
+// the part are appended to the arguments all the same, so the rendered parts
+// must be appended to the query in the order they were rendered.
+func (b *builder) Render(part serializable) string {
+	if b.err != nil || part == nil {
+		return ""
+	}
+	start := b.query.Len()
+	part.serialize(b)
+	rendered := b.query.String()[start:]
+	b.query.Truncate(start)
+	return rendered
+}
@@ -43,3 +43,33 @@ func (c cSqlFuncColumnList) Describe() (output string) {
 	}
 	return strings.Join(parts, ", ")
 }
+
+// cSqlFuncArgList is the arguments of a function, which are columns or
+// literal values
+type cSqlFuncArgList []serializable
+
+func (c cSqlFuncArgList) serialize(b *builder) {
+	b.AppendItems(c, ", ")
+}
+
+func (c cSqlFuncArgList) columns() []Column {
+	var columns []Column
+	for _, arg := range c {
+		if col, ok := arg.(Column); ok {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+func (c cSqlFuncArgList) Describe() (output string) {
+	var parts []string
+	for _, arg := range c {
+		if col, ok := arg.(Column); ok {
+			parts = append(parts, col.column_name())
+		} else {
+			parts = append(parts, arg.Describe())
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+// DatePart is a part of a date and time, used by DateTrunc and Extract
+type DatePart string
+
+const (
+	DatePartYear   DatePart = "YEAR"
+	DatePartMonth  DatePart = "MONTH"
+	DatePartDay    DatePart = "DAY"
+	DatePartHour   DatePart = "HOUR"
+	DatePartMinute DatePart = "MINUTE"
+	DatePartSecond DatePart = "SECOND"
+)
+
+// Valid reports whether the date part is one of the DatePart constants
+func (p DatePart) Valid() bool {
+	switch p {
+	case DatePartYear, DatePartMonth, DatePartDay, DatePartHour, DatePartMinute, DatePartSecond:
+		return true
+	}
+	return false
+}
+
+// cKeyword is an SQL keyword used as the argument of a function
+type cKeyword string
+
+func (c cKeyword) serialize(b *builder) {
+	b.Append(string(c))
+}
+
+func (c cKeyword) Describe() (output string) {
+	return string(c)
+}
+
+// toFuncArgs returns the arguments of a function, values which are not
+// Columns are given as placeholder arguments
+func toFuncArgs(values ...interface{}) cSqlFuncArgList {
+	args := make(cSqlFuncArgList, len(values))
+	for i, value := range values {
+		if col, ok := value.(Column); ok {
+			args[i] = col
+		} else {
+			args[i] = toLiteral(value)
+		}
+	}
+	return args
+}
+
+func newAggregateFunc(name string, value interface{}) SqlFunc {
+	return &cSqlFunc{
+		name: name,
+		args: toFuncArgs(value),
+	}
+}
+
+func newLibraryFunc(name string, values ...interface{}) *cSqlFunc {
+	return &cSqlFunc{
+		name:    name,
+		args:    toFuncArgs(values...),
+		library: true,
+	}
+}
+
+func newDatePartFunc(name string, part DatePart, value interface{}) SqlFunc {
+	fn := newLibraryFunc(name, value)
+	fn.args = append(cSqlFuncArgList{cKeyword(part)}, fn.args...)
+	if !part.Valid() {
+		fn.err = newError("unknown date part %q.", string(part))
+	}
+	return fn
+}
+
+// Count returns the "COUNT(value)" aggregate function, use Star to count the
+// rows.
+func Count(value interface{}) SqlFunc {
+	return newAggregateFunc("COUNT", value)
+}
+
+// Sum returns the "SUM(value)" aggregate function.
+func Sum(value interface{}) SqlFunc {
+	return newAggregateFunc("SUM", value)
+}
+
+// Avg returns the "AVG(value)" aggregate function.
+func Avg(value interface{}) SqlFunc {
+	return newAggregateFunc("AVG", value)
+}
+
+// Min returns the "MIN(value)" aggregate function.
+func Min(value interface{}) SqlFunc {
+	return newAggregateFunc("MIN", value)
+}
+
+// Max returns the "MAX(value)" aggregate function.
+func Max(value interface{}) SqlFunc {
+	return newAggregateFunc("MAX", value)
+}
+
+// Coalesce returns the "COALESCE(values...)" function, the first of the values
+// which is not NULL.
+func Coalesce(values ...interface{}) SqlFunc {
+	fn := newLibraryFunc("COALESCE", values...)
+	if len(values) == 0 {
+		fn.err = newError("COALESCE needs at least one value.")
+	}
+	return fn
+}
+
+// NullIf returns the "NULLIF(value, other)" function, NULL when the value
+// equals the other and the value otherwise.
+func NullIf(value, other interface{}) SqlFunc {
+	return newLibraryFunc("NULLIF", value, other)
+}
+
+// Lower returns the "LOWER(value)" function, the value in lower case.
+func Lower(value interface{}) SqlFunc {
+	return newLibraryFunc("LOWER", value)
+}
+
+// Upper returns the "UPPER(value)" function, the value in upper case.
+func Upper(value interface{}) SqlFunc {
+	return newLibraryFunc("UPPER", value)
+}
+
+// Length returns the function counting the characters of the value,
+// "LENGTH(value)" or "CHAR_LENGTH(value)" depending on the dialect.
+func Length(value interface{}) SqlFunc {
+	return newLibraryFunc("LENGTH", value)
+}
+
+// Round returns the "ROUND(value, decimals)" function, the value rounded to
+// the number of decimals.
+func Round(value interface{}, decimals int) SqlFunc {
+	return newLibraryFunc("ROUND", value, decimals)
+}
+
+// Abs returns the "ABS(value)" function, the absolute value.
+func Abs(value interface{}) SqlFunc {
+	return newLibraryFunc("ABS", value)
+}
+
+// Now returns the function of the current date and time, "NOW()" or
+// "datetime('now')" depending on the dialect.
+func Now() SqlFunc {
+	return newLibraryFunc("NOW")
+}
+
+// DateTrunc returns the function truncating the date and time of the value to
+// the part given (ex: the first second of the day for DatePartDay).
+func DateTrunc(part DatePart, value interface{}) SqlFunc {
+	return newDatePartFunc("DATE_TRUNC", part, value)
+}
+
+// Extract returns the function extracting the part given, as a number, from
+// the date and time of the value (ex: the month for DatePartMonth).
+func Extract(part DatePart, value interface{}) SqlFunc {
+	return newDatePartFunc("EXTRACT", part, value)
+}
@@ -29,20 +29,67 @@ import (
 type SqlFunc interface {
 	Column
 
+	// Over returns the window function applying the function over the
+	// window given, with "OVER (...)" clause
+	Over(window WindowSpec) SqlFunc
+	// OverWindow returns the window function applying the function over the
+	// window named, defined by the WINDOW clause of the SELECT statement
+	OverWindow(name string) SqlFunc
+	// Distinct returns the aggregate function applied to the distinct values
+	// only, with "DISTINCT" clause
+	Distinct() SqlFunc
+
 	columns() []Column
 }
 
 // Func returns new SQL function.  The name is function name, and the args is arguments of function
 func Func(name string, args ...Column) SqlFunc {
+	list := make(cSqlFuncArgList, len(args))
+	for i := range args {
+		list[i] = args[i]
+	}
 	return &cSqlFunc{
 		name: name,
-		args: args,
+		args: list,
 	}
 }
 
 type cSqlFunc struct {
-	name string
-	args cSqlFuncColumnList
+	name     string
+	args     cSqlFuncArgList
+	distinct bool
+
+	// library functions are written by the dialect, see FuncToString
+	library bool
+
+	window     WindowSpec
+	windowName string
+
+	err error
+}
+
+func (c *cSqlFunc) Distinct() SqlFunc {
+	fn := *c
+	fn.distinct = true
+	if c.library {
+		fn.err = newError("DISTINCT can not be used with %s function.", c.name)
+	}
+	return &fn
+}
+
+func (c *cSqlFunc) Over(window WindowSpec) SqlFunc {
+	fn := *c
+	fn.window, fn.windowName = window, ""
+	if window == nil {
+		fn.window = Window()
+	}
+	return &fn
+}
+
+func (c *cSqlFunc) OverWindow(name string) SqlFunc {
+	fn := *c
+	fn.window, fn.windowName = nil, name
+	return &fn
 }
 
 func (c *cSqlFunc) As(alias string) Column {
@@ -73,27 +120,44 @@ func (c *cSqlFunc) acceptType(interface{}) bool {
 }
 
 func (c *cSqlFunc) serialize(b *builder) {
-	b.Append(c.name)
-	b.Append("(")
-	b.AppendItem(c.args)
-	b.Append(")")
-}
-
-func (c *cSqlFunc) hasColumn(t *cTable) (present bool) {
-	for _, fncol := range c.columns() {
-		if fncolfn, ok := fncol.(*cSqlFunc); ok {
-			if present = fncolfn.hasColumn(t); present {
-				return
-			}
-		} else {
-			for _, col := range t.columns {
-				if present = SameColumn(col, fncol); present {
-					return
-				}
-			}
+	if c.err != nil {
+		b.SetError(c.err)
+		return
+	}
+	if c.library {
+		args := make([]string, len(c.args))
+		for i, arg := range c.args {
+			args[i] = b.Render(arg)
+		}
+		call, err := b.dialect.FuncToString(c.name, args)
+		if err != nil {
+			b.SetError(err)
+			return
 		}
+		b.Append(call)
+	} else {
+		b.Append(c.name)
+		b.Append("(")
+		if c.distinct {
+			b.Append("DISTINCT ")
+		}
+		b.AppendItem(c.args)
+		b.Append(")")
+	}
+	if c.window != nil {
+		b.Append(" OVER ")
+		b.AppendItem(c.window)
+	} else if c.windowName != "" {
+		if !b.windows[c.windowName] {
+			b.SetError(newError("window %s is not defined.", c.windowName))
+			return
+		}
+		b.Append(" OVER " + b.dialect.QuoteField(c.windowName))
 	}
-	return
+}
+
+func (c *cSqlFunc) hasColumn(t *cTable) bool {
+	return hasExpressionColumn(t, c.columns())
 }
 
 func (c *cSqlFunc) Eq(right interface{}) Condition {
@@ -140,11 +204,43 @@ func (c *cSqlFunc) NotIn(vals ...interface{}) Condition {
 	return newInCondition(true, c, vals...)
 }
 
+func (c *cSqlFunc) Add(right interface{}) Column {
+	return newExpression("+", c, right)
+}
+
+func (c *cSqlFunc) Sub(right interface{}) Column {
+	return newExpression("-", c, right)
+}
+
+func (c *cSqlFunc) Mul(right interface{}) Column {
+	return newExpression("*", c, right)
+}
+
+func (c *cSqlFunc) Div(right interface{}) Column {
+	return newExpression("/", c, right)
+}
+
+func (c *cSqlFunc) Mod(right interface{}) Column {
+	return newExpression("%", c, right)
+}
+
 func (c *cSqlFunc) columns() []Column {
-	return c.args
+	if c.window != nil {
+		return append(c.args.columns(), c.window.columns()...)
+	}
+	return c.args.columns()
 }
 
 func (c *cSqlFunc) Describe() (output string) {
-	output = fmt.Sprintf("%q(%s)", c.name, c.args.Describe())
+	if c.distinct {
+		output = fmt.Sprintf("%q(DISTINCT %s)", c.name, c.args.Describe())
+	} else {
+		output = fmt.Sprintf("%q(%s)", c.name, c.args.Describe())
+	}
+	if c.window != nil {
+		output += " OVER " + c.window.Describe()
+	} else if c.windowName != "" {
+		output += fmt.Sprintf(" OVER %q", c.windowName)
+	}
 	return
 }
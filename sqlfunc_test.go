@@ -56,3 +56,193 @@ func TestSqlFunc(t *testing.T) {
 		t.Errorf("failed")
 	}
 }
+
+func TestWindowFunc(t *testing.T) {
+	table1 := NewTable(
+		"TABLE_A",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		IntColumn("test1", nil),
+		IntColumn("test2", nil),
+	)
+	table2 := NewTable("TABLE_B", &TableOption{}, IntColumn("id", nil))
+
+	rowNumber := Func("ROW_NUMBER").Over(Window().
+		PartitionBy(table1.C("test1")).
+		OrderBy(true, table1.C("test2")).
+		OrderBy(false, table1.C("id"))).As("rn")
+	ranked := Select(table1).Columns(table1.C("id"), rowNumber).ToSubquery("RANKED")
+
+	var cases = []statementTestCase{{
+		stmt: Select(table1).Columns(
+			table1.C("id"),
+			Func("ROW_NUMBER").Over(Window().PartitionBy(table1.C("test1")).OrderBy(true, table1.C("test2"))),
+		),
+		query:  `SELECT "TABLE_A"."id", ROW_NUMBER() OVER (PARTITION BY "TABLE_A"."test1" ORDER BY "TABLE_A"."test2" DESC) FROM "TABLE_A";`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt: Select(table1).Columns(
+			Func("SUM", table1.C("test1")).Over(Window().OrderBy(false, table1.C("id")).Rows(UnboundedPreceding, CurrentRow)).As("total"),
+			Func("AVG", table1.C("test1")).Over(Window().OrderBy(false, table1.C("id")).Range(Preceding(2), Following(1))),
+			Func("COUNT", Star).Over(nil),
+		),
+		query: `SELECT SUM("TABLE_A"."test1") OVER (ORDER BY "TABLE_A"."id" ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS "total", ` +
+			`AVG("TABLE_A"."test1") OVER (ORDER BY "TABLE_A"."id" ASC RANGE BETWEEN 2 PRECEDING AND 1 FOLLOWING), ` +
+			`COUNT(*) OVER () FROM "TABLE_A";`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt: Select(table1).
+			Columns(Func("RANK").OverWindow("w"), Func("SUM", table1.C("test2")).OverWindow("w")).
+			Window("w", Window().PartitionBy(table1.C("test1"), table1.C("test2")).OrderBy(false, table1.C("id"))).
+			OrderBy(false, table1.C("id")),
+		query:  `SELECT RANK() OVER "w", SUM("TABLE_A"."test2") OVER "w" FROM "TABLE_A" WINDOW "w" AS (PARTITION BY "TABLE_A"."test1", "TABLE_A"."test2" ORDER BY "TABLE_A"."id" ASC) ORDER BY "TABLE_A"."id" ASC;`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt: Select(ranked).Columns(ranked.C("id"), ranked.C("rn")).Where(ranked.C("rn").LtEq(3)),
+		query: `SELECT "RANKED"."id", "RANKED"."rn" FROM ( SELECT "TABLE_A"."id", ROW_NUMBER() OVER ` +
+			`(PARTITION BY "TABLE_A"."test1" ORDER BY "TABLE_A"."test2" DESC, "TABLE_A"."id" ASC) AS "rn" FROM "TABLE_A" ) AS RANKED ` +
+			`WHERE "RANKED"."rn"<=?;`,
+		args:   []interface{}{int64(3)},
+		errmsg: "",
+	}, {
+		stmt:   Select(table1).Columns(Func("RANK").Over(Window().PartitionBy(table2.C("id")))),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: `sqlbuilder: column not found in FROM: "RANK"`,
+	}, {
+		stmt:   Select(table1).Window("w", Window().OrderBy(false, table2.C("id"))),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: `sqlbuilder: column not found in FROM: "id"`,
+	}, {
+		stmt:   Select(table1).Window("w", Window()).Window("w", Window()),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: window w is already defined.",
+	}, {
+		stmt:   Select(table1).Columns(Func("SUM", table1.C("id")).Over(Window().Rows(CurrentRow, Preceding(1)))),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: frame starts with CURRENT ROW, after its end 1 PRECEDING.",
+	}, {
+		stmt:   Select(table1).Columns(Func("SUM", table1.C("id")).Over(Window().Rows(UnboundedFollowing, UnboundedFollowing))),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: frame can not start with UNBOUNDED FOLLOWING.",
+	}, {
+		stmt:   Select(table1).Columns(Func("SUM", table1.C("id")).Over(Window().Rows(Preceding(5), Preceding(2)))),
+		query:  `SELECT SUM("TABLE_A"."id") OVER (ROWS BETWEEN 5 PRECEDING AND 2 PRECEDING) FROM "TABLE_A";`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt:   Select(table1).Columns(Func("SUM", table1.C("id")).Over(Window().Rows(Preceding(2), Preceding(5)))),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: frame starts with 2 PRECEDING, after its end 5 PRECEDING.",
+	}, {
+		stmt:   Select(table1).Columns(Func("SUM", table1.C("id")).Over(Window().Range(Following(5), Following(2)))),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: frame starts with 5 FOLLOWING, after its end 2 FOLLOWING.",
+	}, {
+		stmt:   Select(table1).Columns(Func("RANK").OverWindow("w")).Window("v", Window()),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: window w is not defined.",
+	}}
+	for num, c := range cases {
+		mes, args, ok := c.Run()
+		if !ok {
+			t.Errorf(mes+" (case no.%d)", append(args, num)...)
+		}
+	}
+}
+
+func TestSqlFuncLibrary(t *testing.T) {
+	table1 := NewTable(
+		"TABLE_A",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+		StringColumn("name", nil),
+		FloatColumn("price", nil),
+		DateColumn("created", nil),
+	)
+
+	var cases = []statementTestCase{{
+		stmt: Select(table1).Columns(
+			Count(Star),
+			Count(table1.C("name")).Distinct(),
+			Sum(table1.C("price")),
+			Avg(table1.C("price")).Distinct(),
+			Min(table1.C("id")),
+			Max(table1.C("created")),
+		),
+		query: `SELECT COUNT(*), COUNT(DISTINCT "TABLE_A"."name"), SUM("TABLE_A"."price"), AVG(DISTINCT "TABLE_A"."price"), ` +
+			`MIN("TABLE_A"."id"), MAX("TABLE_A"."created") FROM "TABLE_A";`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt: Select(table1).Columns(
+			Coalesce(table1.C("price"), 0).As("price"),
+			NullIf(table1.C("name"), ""),
+			Upper(Lower(table1.C("name"))),
+			Round(Abs(table1.C("price")), 2),
+		),
+		query: `SELECT COALESCE("TABLE_A"."price", ?) AS "price", NULLIF("TABLE_A"."name", ?), UPPER(LOWER("TABLE_A"."name")), ` +
+			`ROUND(ABS("TABLE_A"."price"), ?) FROM "TABLE_A";`,
+		args:   []interface{}{int64(0), "", int64(2)},
+		errmsg: "",
+	}, {
+		stmt: Select(table1).
+			Columns(DateTrunc(DatePartDay, table1.C("created")).As("day"), Count(Star)).
+			Where(And(Length(table1.C("name")).Gt(3), Extract(DatePartYear, table1.C("created")).Eq(2024), table1.C("created").Lt(Now()))).
+			GroupBy(DateTrunc(DatePartDay, table1.C("created"))),
+		query: `SELECT DATE_TRUNC('day', "TABLE_A"."created") AS "day", COUNT(*) FROM "TABLE_A" ` +
+			`WHERE LENGTH("TABLE_A"."name")>? AND EXTRACT(YEAR FROM "TABLE_A"."created")=? AND "TABLE_A"."created"<NOW() ` +
+			`GROUP BY DATE_TRUNC('day', "TABLE_A"."created");`,
+		args:   []interface{}{int64(3), int64(2024)},
+		errmsg: "",
+	}, {
+		stmt:   Update(table1).Set(table1.C("name"), Coalesce(table1.C("name"), "unknown")),
+		query:  `UPDATE "TABLE_A" SET "name"=COALESCE("TABLE_A"."name", ?);`,
+		args:   []interface{}{"unknown"},
+		errmsg: "",
+	}, {
+		stmt:   Select(table1).Columns(Sum(table1.C("price")).Over(Window().OrderBy(false, table1.C("id")))),
+		query:  `SELECT SUM("TABLE_A"."price") OVER (ORDER BY "TABLE_A"."id" ASC) FROM "TABLE_A";`,
+		args:   []interface{}{},
+		errmsg: "",
+	}, {
+		stmt:   Select(table1).Columns(Lower(table1.C("name")).Distinct()),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: DISTINCT can not be used with LOWER function.",
+	}, {
+		stmt:   Select(table1).Columns(Extract(DatePart("WEEK"), table1.C("created"))),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: `sqlbuilder: unknown date part "WEEK".`,
+	}, {
+		stmt:   Select(table1).Columns(Coalesce()),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: COALESCE needs at least one value.",
+	}}
+	for num, c := range cases {
+		mes, args, ok := c.Run()
+		if !ok {
+			t.Errorf(mes+" (case no.%d)", append(args, num)...)
+		}
+	}
+
+	if _, err := (TestingDialect{}).FuncToString("DATE_TRUNC", []string{"DAY"}); err == nil || err.Error() != "sqlbuilder: DATE_TRUNC function needs 2 arguments, got 1." {
+		t.Errorf("failed: %v", err)
+	}
+}
@@ -47,5 +47,4 @@ func (t tableJoinType) String() (name string) {
 	default:
 		panic(fmt.Errorf("unknown table join type: %q", int(t)))
 	}
-	return
 }
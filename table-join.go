@@ -126,6 +126,10 @@ func (c *cTableJoin) serialize(b *builder) {
 	case *cTableJoin:
 
 		c.writeJoin(b, c.join, t.LeftName(), c.on)
+
+	case *cCommonTable:
+
+		c.writeJoin(b, c.join, t.name, c.on)
 	}
 
 	return
@@ -167,6 +171,8 @@ func (c *cTableJoin) Describe() (output string) {
 		output += c.join.String() + " " + t.Name()
 	case *cTableJoin:
 		output += c.join.String() + " " + t.RightName()
+	case *cCommonTable:
+		output += c.join.String() + " " + t.Name()
 	}
 
 	output += " ON (" + c.on.Describe() + ")"
@@ -25,28 +25,241 @@ import (
 	"strconv"
 )
 
-// TODO: TableOption.ForeignKey map[string]Column
 // TODO: convert TableOption to a buildable design pattern
 
 // TableOption represents constraint of a table.
 type TableOption struct {
-	Unique [][]string
+	PrimaryKey  []string
+	Unique      [][]string
+	Checks      []Check
+	ForeignKeys []ForeignKey
+}
+
+// Check represents a CHECK constraint of a table. Name is the optional
+// constraint name. The Condition may only use columns of the table, matched
+// by name, and is written with its values inlined as CREATE TABLE can not
+// take bind variables.
+type Check struct {
+	Name      string
+	Condition Condition
+}
+
+// check returns an error if the Condition is missing or uses columns not
+// found in the table given
+func (c Check) check(t *cTable) error {
+	if c.Condition == nil {
+		return newError("check constraint needs a condition.")
+	}
+	for _, col := range c.Condition.columns() {
+		if !t.hasColumnName(col.column_name()) {
+			return newError("column %s.%s was not found.", t.name, col.column_name())
+		}
+	}
+	return nil
+}
+
+// uses reports whether the Condition uses the column named by the name
+func (c Check) uses(name string) bool {
+	if c.Condition == nil {
+		return false
+	}
+	for _, col := range c.Condition.columns() {
+		if col.column_name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// toString returns the CHECK expression of the Condition with unqualified
+// column names and inlined values
+func (c Check) toString(d Dialect) (string, error) {
+	b := newBuilder(d)
+	b.inline = true
+	b.AppendItem(c.Condition)
+	if err := b.Err(); err != nil {
+		return "", err
+	}
+	return b.query.String(), nil
+}
+
+// Describe returns a string representation of the Check
+func (c Check) Describe() (output string) {
+	if c.Name != "" {
+		output += strconv.Quote(c.Name) + ": "
+	}
+	if c.Condition != nil {
+		output += c.Condition.Describe()
+	}
+	return
+}
+
+// cRenamedCondition is a check condition of which columns were renamed by
+// ChangeColumn. The names map the column names used by the condition to the
+// current names in the table.
+type cRenamedCondition struct {
+	cond  Condition
+	table *cTable
+	names map[string]string
+}
+
+func renameCondition(cond Condition, t *cTable, old, new string) Condition {
+	names := make(map[string]string)
+	if renamed, ok := cond.(*cRenamedCondition); ok {
+		for k, v := range renamed.names {
+			names[k] = v
+		}
+		cond = renamed.cond
+	}
+	for k, v := range names {
+		if v == old {
+			names[k] = new
+		}
+	}
+	if _, ok := names[old]; !ok {
+		names[old] = new
+	}
+	return &cRenamedCondition{
+		cond:  cond,
+		table: t,
+		names: names,
+	}
+}
+
+func (c *cRenamedCondition) serialize(b *builder) {
+	renames := b.renames
+	b.renames = c.names
+	b.AppendItem(c.cond)
+	b.renames = renames
+}
+
+func (c *cRenamedCondition) columns() []Column {
+	columns := c.cond.columns()
+	renamed := make([]Column, 0, len(columns))
+	for _, col := range columns {
+		if name, ok := c.names[col.column_name()]; ok {
+			col = c.table.C(name)
+		}
+		renamed = append(renamed, col)
+	}
+	return renamed
+}
+
+func (c *cRenamedCondition) Describe() (output string) {
+	return c.cond.Describe()
+}
+
+// ForeignKeyAction represents the referential action of a foreign key.
+// Dialects handle this for known foreign key options.
+type ForeignKeyAction int
+
+const (
+	ForeignKeyNoAction ForeignKeyAction = iota
+	ForeignKeyCascade
+	ForeignKeySetNull
+	ForeignKeyRestrict
+)
+
+func (a ForeignKeyAction) String() string {
+	switch a {
+	case ForeignKeyNoAction:
+		return "NO ACTION"
+	case ForeignKeyCascade:
+		return "CASCADE"
+	case ForeignKeySetNull:
+		return "SET NULL"
+	case ForeignKeyRestrict:
+		return "RESTRICT"
+	}
+	return "ForeignKeyAction(" + strconv.Itoa(int(a)) + ")"
+}
+
+// Valid reports whether the action is one of the ForeignKeyAction constants
+func (a ForeignKeyAction) Valid() bool {
+	switch a {
+	case ForeignKeyNoAction, ForeignKeyCascade, ForeignKeySetNull, ForeignKeyRestrict:
+		return true
+	}
+	return false
+}
+
+// ForeignKey represents a foreign key constraint of a table. The Columns of
+// the table reference the RefColumns of the RefTable, in order. RefColumns
+// may be empty to reference the primary key of the RefTable. Name is the
+// optional constraint name and is needed to drop the foreign key later.
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   ForeignKeyAction
+	OnUpdate   ForeignKeyAction
+}
+
+// check returns an error if the foreign key is incomplete or uses columns
+// not found in the table given
+func (fk ForeignKey) check(t *cTable) error {
+	if len(fk.Columns) == 0 {
+		return newError("foreign key needs one or more columns.")
+	}
+	if len(fk.RefTable) == 0 {
+		return newError("foreign key needs a referenced table.")
+	}
+	if len(fk.RefColumns) != 0 && len(fk.RefColumns) != len(fk.Columns) {
+		return newError("foreign key has %d columns, but references %d.", len(fk.Columns), len(fk.RefColumns))
+	}
+	for _, name := range fk.Columns {
+		if !t.hasColumnName(name) {
+			return newError("column %s.%s was not found.", t.name, name)
+		}
+	}
+	return nil
+}
+
+// Describe returns a string representation of the ForeignKey
+func (fk ForeignKey) Describe() (output string) {
+	if fk.Name != "" {
+		output += strconv.Quote(fk.Name) + ": "
+	}
+	output += describeNames(fk.Columns)
+	output += " -> " + strconv.Quote(fk.RefTable)
+	if len(fk.RefColumns) > 0 {
+		output += describeNames(fk.RefColumns)
+	}
+	if fk.OnDelete != ForeignKeyNoAction {
+		output += " ON DELETE " + fk.OnDelete.String()
+	}
+	if fk.OnUpdate != ForeignKeyNoAction {
+		output += " ON UPDATE " + fk.OnUpdate.String()
+	}
+	return
 }
 
 // Describe returns a string representation of the TableOption
 func (t TableOption) Describe() (output string) {
-	if len(t.Unique) == 0 {
-		return
+	if len(t.PrimaryKey) > 0 {
+		output += ".PrimaryKey" + describeNames(t.PrimaryKey)
 	}
 	for idx, list := range t.Unique {
-		output += ".Unique[" + strconv.Itoa(idx) + "]("
-		for jdx, key := range list {
-			if jdx > 0 {
-				output += ", "
-			}
-			output += strconv.Quote(key)
+		output += ".Unique[" + strconv.Itoa(idx) + "]" + describeNames(list)
+	}
+	for idx, check := range t.Checks {
+		output += ".Checks[" + strconv.Itoa(idx) + "](" + check.Describe() + ")"
+	}
+	for idx, fk := range t.ForeignKeys {
+		output += ".ForeignKeys[" + strconv.Itoa(idx) + "](" + fk.Describe() + ")"
+	}
+	return
+}
+
+func describeNames(names []string) (output string) {
+	output += "("
+	for idx, name := range names {
+		if idx > 0 {
+			output += ", "
 		}
-		output += ")"
+		output += strconv.Quote(name)
 	}
+	output += ")"
 	return
 }
@@ -0,0 +1,273 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"database/sql"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// structColumn is a column of a table defined by a struct field
+type structColumn struct {
+	index     []int
+	config    ColumnConfig
+	omitempty bool
+}
+
+// NewTableFromStruct returns a new table named by the name, with a column for
+// each exported field of the structure given (a struct or pointer to one).
+// Fields of embedded structs are included. The column of a field is
+// configured by the ScanTag struct tag, a comma separated list starting with
+// the column name (the field name when empty) followed by any of:
+//
+//	primary        | ColumnOption.PrimaryKey, or TableOption.PrimaryKey if
+//	               | used by more than one field
+//	notnull        | ColumnOption.NotNull
+//	unique         | ColumnOption.Unique
+//	autoincrement  | ColumnOption.AutoIncrement
+//	size=<n>       | ColumnOption.Size
+//	type=<sql>     | ColumnOption.SqlType
+//	default=<v>    | ColumnOption.Default, parsed for the ColumnType
+//	omitempty      | skipped by InsertBuilder.FromStruct when zero
+//
+// The ColumnType of a field is the one whose CapableTypes include the field
+// type, which may be a pointer or an sql.Null type of one. Fields of other
+// types need a type=<sql> option and are ColumnTypeAny. A tag of "-" skips
+// the field.
+func NewTableFromStruct(name string, structure interface{}) (Table, error) {
+	typ := reflect.TypeOf(structure)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, newError("got %T type, but NewTableFromStruct needs a struct.", structure)
+	}
+	columns, err := structColumns(typ)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, newError("%s has no exported fields.", typ)
+	}
+
+	option := &TableOption{}
+	configs := make([]ColumnConfig, len(columns))
+	for i, col := range columns {
+		configs[i] = col.config
+		if col.config.Option().PrimaryKey {
+			option.PrimaryKey = append(option.PrimaryKey, col.config.Name())
+		}
+	}
+	if len(option.PrimaryKey) > 1 {
+		// composite primary keys are a table constraint
+		for _, cc := range configs {
+			cc.Option().PrimaryKey = false
+		}
+	} else {
+		option.PrimaryKey = nil
+	}
+	return NewTable(name, option, configs...), nil
+}
+
+// structColumns returns the columns defined by the fields of the struct type
+func structColumns(typ reflect.Type) (columns []structColumn, err error) {
+	seen := make(map[string]bool)
+	var walk func(t reflect.Type, index []int) error
+	walk = func(t reflect.Type, index []int) error {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag, tagged := field.Tag.Lookup(ScanTag)
+			if tag == "-" {
+				continue
+			}
+			path := append(append([]int(nil), index...), i)
+			if field.Anonymous && field.Type.Kind() == reflect.Struct && !tagged {
+				if err := walk(field.Type, path); err != nil {
+					return err
+				}
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+			col, err := structFieldColumn(field, tag)
+			if err != nil {
+				return err
+			}
+			if seen[col.config.Name()] {
+				return newError("field %s: column %q is already defined.", field.Name, col.config.Name())
+			}
+			seen[col.config.Name()] = true
+			col.index = path
+			columns = append(columns, col)
+		}
+		return nil
+	}
+	err = walk(typ, nil)
+	return
+}
+
+func structFieldColumn(field reflect.StructField, tag string) (col structColumn, err error) {
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+
+	opt := &ColumnOption{}
+	var defaultValue *string
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "primary":
+			opt.PrimaryKey = true
+		case "notnull":
+			opt.NotNull = true
+		case "unique":
+			opt.Unique = true
+		case "autoincrement":
+			opt.AutoIncrement = true
+		case "omitempty":
+			col.omitempty = true
+		case "size":
+			if opt.Size, err = strconv.Atoi(value); err != nil {
+				return col, newError("field %s: size is not a number: %q", field.Name, value)
+			}
+		case "type":
+			opt.SqlType = value
+		case "default":
+			defaultValue = &value
+		case "":
+		default:
+			return col, newError("field %s: unknown option %q.", field.Name, key)
+		}
+	}
+
+	typ, ok := structFieldType(field.Type)
+	if !ok {
+		if opt.SqlType == "" {
+			return col, newError("field %s: %s type needs a type=<sql> option.", field.Name, field.Type)
+		}
+		typ = ColumnTypeAny
+	}
+	if defaultValue != nil {
+		if opt.Default, err = parseStructDefault(typ, *defaultValue); err != nil {
+			return col, newError("field %s: default is not a %s: %q", field.Name, typ, *defaultValue)
+		}
+	}
+	col.config = newColumnImplConfig(name, typ, opt)
+	return
+}
+
+// structFieldValues returns the columns of the table matching the fields of
+// the struct value given, by column name, with the field values. Fields tagged
+// omitempty are skipped when zero, as are the columns skip reports true for.
+func structFieldValues(tbl *cTable, structure interface{}, skip func(col Column) bool) (columns []Column, values []interface{}, err error) {
+	value := reflect.ValueOf(structure)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, nil, newError("got %T type, but a struct is needed.", structure)
+	}
+	fields, err := structColumns(value.Type())
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, field := range fields {
+		if !tbl.hasColumnName(field.config.Name()) {
+			continue
+		}
+		col := tbl.C(field.config.Name())
+		if skip != nil && skip(col) {
+			continue
+		}
+		fv := value.FieldByIndex(field.index)
+		if field.omitempty && fv.IsZero() {
+			continue
+		}
+		columns = append(columns, col)
+		values = append(values, fv.Interface())
+	}
+	if len(columns) == 0 {
+		return nil, nil, newError("%s has no fields for the columns of %s.", value.Type(), tbl.name)
+	}
+	return
+}
+
+var (
+	gStructNullTypes = map[reflect.Type]ColumnType{
+		reflect.TypeOf(sql.NullString{}):  ColumnTypeString,
+		reflect.TypeOf(sql.NullInt64{}):   ColumnTypeInt,
+		reflect.TypeOf(sql.NullInt32{}):   ColumnTypeInt,
+		reflect.TypeOf(sql.NullInt16{}):   ColumnTypeInt,
+		reflect.TypeOf(sql.NullByte{}):    ColumnTypeInt,
+		reflect.TypeOf(sql.NullFloat64{}): ColumnTypeFloat,
+		reflect.TypeOf(sql.NullBool{}):    ColumnTypeBool,
+		reflect.TypeOf(sql.NullTime{}):    ColumnTypeDate,
+	}
+	gStructColumnTypes = []ColumnType{
+		ColumnTypeInt,
+		ColumnTypeString,
+		ColumnTypeDate,
+		ColumnTypeFloat,
+		ColumnTypeBool,
+		ColumnTypeBytes,
+	}
+)
+
+// structFieldType returns the ColumnType capable of the field type given
+func structFieldType(typ reflect.Type) (ColumnType, bool) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if ct, ok := gStructNullTypes[typ]; ok {
+		return ct, true
+	}
+	for _, ct := range gStructColumnTypes {
+		for _, capable := range ct.CapableTypes() {
+			if capable == typ {
+				return ct, true
+			}
+		}
+	}
+	return ColumnTypeAny, false
+}
+
+func parseStructDefault(typ ColumnType, value string) (interface{}, error) {
+	switch typ {
+	case ColumnTypeInt:
+		return strconv.ParseInt(value, 10, 64)
+	case ColumnTypeFloat:
+		return strconv.ParseFloat(value, 64)
+	case ColumnTypeBool:
+		return strconv.ParseBool(value)
+	case ColumnTypeDate:
+		if _, err := time.Parse("2006-01-02 15:04:05", value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
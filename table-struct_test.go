@@ -0,0 +1,240 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+type structTimestamps struct {
+	Created time.Time  `sql:"created,notnull,default=2024-01-02 03:04:05"`
+	Updated *time.Time `sql:"updated"`
+}
+
+type structPerson struct {
+	Id   int64  `sql:"id,primary,autoincrement"`
+	Name string `sql:"name,notnull,unique,size=64"`
+	Nick sql.NullString
+	Age  *int     `sql:"age,default=18"`
+	Data []byte   `sql:"data"`
+	Rate float32  `sql:",default=1.5"`
+	Tags []string `sql:"tags,type=JSON"`
+	Temp string   `sql:"-"`
+	note string
+	structTimestamps
+}
+
+type structMembership struct {
+	GroupId  int64 `sql:"group_id,primary"`
+	PersonId int64 `sql:"person_id,primary"`
+	Admin    bool  `sql:"admin,default=false"`
+}
+
+func TestNewTableFromStruct(t *testing.T) {
+	people, err := NewTableFromStruct("PERSON", &structPerson{})
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	query, _, err := CreateTable(people).ToSql()
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	expect := `CREATE TABLE "PERSON" ( "id" INTEGER PRIMARY KEY AUTOINCREMENT, "name" TEXT NOT NULL UNIQUE, ` +
+		`"Nick" TEXT, "age" INTEGER, "data" BLOB, "Rate" REAL, "tags" JSON, "created" DATE NOT NULL, "updated" DATE );`
+	if query != expect {
+		t.Errorf("failed\nexpect: %s\ngot: %s", expect, query)
+	}
+	if opt := people.C("name").config().Option(); opt.Size != 64 {
+		t.Errorf("failed: size %d", opt.Size)
+	}
+	if opt := people.C("age").config().Option(); opt.Default != int64(18) {
+		t.Errorf("failed: default %#v", opt.Default)
+	}
+	if opt := people.C("Rate").config().Option(); opt.Default != float64(1.5) {
+		t.Errorf("failed: default %#v", opt.Default)
+	}
+	if typ := people.C("tags").config().Type(); typ != ColumnTypeAny {
+		t.Errorf("failed: type %s", typ)
+	}
+
+	memberships, err := NewTableFromStruct("MEMBERSHIP", structMembership{})
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	query, _, err = CreateTable(memberships).ToSql()
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	expect = `CREATE TABLE "MEMBERSHIP" ( "group_id" INTEGER, "person_id" INTEGER, "admin" BOOLEAN, PRIMARY KEY ("group_id", "person_id") );`
+	if query != expect {
+		t.Errorf("failed\nexpect: %s\ngot: %s", expect, query)
+	}
+
+	var cases = []struct {
+		structure interface{}
+		errmsg    string
+	}{{
+		structure: 10,
+		errmsg:    "sqlbuilder: got int type, but NewTableFromStruct needs a struct.",
+	}, {
+		structure: struct{ secret int }{},
+		errmsg:    "sqlbuilder: struct { secret int } has no exported fields.",
+	}, {
+		structure: struct {
+			Tags []string
+		}{},
+		errmsg: "sqlbuilder: field Tags: []string type needs a type=<sql> option.",
+	}, {
+		structure: struct {
+			Id int `sql:"id,size=big"`
+		}{},
+		errmsg: `sqlbuilder: field Id: size is not a number: "big"`,
+	}, {
+		structure: struct {
+			Id int `sql:"id,default=none"`
+		}{},
+		errmsg: `sqlbuilder: field Id: default is not a int: "none"`,
+	}, {
+		structure: struct {
+			Id int `sql:"id,primay"`
+		}{},
+		errmsg: `sqlbuilder: field Id: unknown option "primay".`,
+	}, {
+		structure: struct {
+			Id    int `sql:"id"`
+			Other int `sql:"id"`
+		}{},
+		errmsg: `sqlbuilder: field Other: column "id" is already defined.`,
+	}}
+	for num, c := range cases {
+		_, err := NewTableFromStruct("TABLE", c.structure)
+		if err == nil || err.Error() != c.errmsg {
+			t.Errorf("failed on %d\nexpect: %s\ngot: %v", num, c.errmsg, err)
+		}
+	}
+}
+
+type structArticle struct {
+	Id    int64  `sql:"id,primary,autoincrement"`
+	Title string `sql:"title,notnull"`
+	Body  string `sql:"body,omitempty"`
+	Views int64  `sql:"views,omitempty"`
+	Extra string `sql:"-"`
+}
+
+func TestInsertFromStruct(t *testing.T) {
+	articles, err := NewTableFromStruct("ARTICLE", structArticle{})
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	memberships, err := NewTableFromStruct("MEMBERSHIP", structMembership{})
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	other := NewTable("OTHER", nil, IntColumn("other_id", nil))
+
+	var cases = []statementTestCase{{
+		stmt:   Insert(articles).FromStruct(&structArticle{Id: 5, Title: "title", Body: "body"}),
+		query:  `INSERT INTO "ARTICLE" ( "title", "body" ) VALUES ( ?, ? );`,
+		args:   []interface{}{"title", "body"},
+		errmsg: "",
+	}, {
+		stmt:   Insert(articles).FromStruct(structArticle{Title: "title", Views: 3}),
+		query:  `INSERT INTO "ARTICLE" ( "title", "views" ) VALUES ( ?, ? );`,
+		args:   []interface{}{"title", int64(3)},
+		errmsg: "",
+	}, {
+		stmt:   Insert(memberships).FromStruct(&structMembership{GroupId: 1, PersonId: 2}),
+		query:  `INSERT INTO "MEMBERSHIP" ( "group_id", "person_id", "admin" ) VALUES ( ?, ?, ? );`,
+		args:   []interface{}{int64(1), int64(2), false},
+		errmsg: "",
+	}, {
+		stmt:   Insert(articles).FromStruct(10),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: got int type, but a struct is needed.",
+	}, {
+		stmt:   Insert(other).FromStruct(&structArticle{Title: "title"}),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: sqlbuilder.structArticle has no fields for the columns of OTHER.",
+	}}
+	for num, c := range cases {
+		mes, args, ok := c.Run()
+		if !ok {
+			t.Errorf(mes+" (case no.%d)", append(args, num)...)
+		}
+	}
+}
+
+func TestUpdateSetStruct(t *testing.T) {
+	articles, err := NewTableFromStruct("ARTICLE", structArticle{})
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	memberships, err := NewTableFromStruct("MEMBERSHIP", structMembership{})
+	if err != nil {
+		t.Fatalf("failed: %s", err)
+	}
+	other := NewTable("OTHER", nil, IntColumn("id", nil))
+	article := &structArticle{Id: 5, Title: "title", Views: 3}
+
+	var cases = []statementTestCase{{
+		stmt:   Update(articles).SetStruct(article).Where(articles.C("id").Eq(article.Id)),
+		query:  `UPDATE "ARTICLE" SET "title"=?, "views"=? WHERE "ARTICLE"."id"=?;`,
+		args:   []interface{}{"title", int64(3), int64(5)},
+		errmsg: "",
+	}, {
+		stmt:   Update(articles).SetStruct(article, articles.C("views"), articles.C("body")),
+		query:  `UPDATE "ARTICLE" SET "views"=?;`,
+		args:   []interface{}{int64(3)},
+		errmsg: "",
+	}, {
+		stmt:   Update(memberships).SetStruct(structMembership{GroupId: 1, PersonId: 2, Admin: true}),
+		query:  `UPDATE "MEMBERSHIP" SET "admin"=?;`,
+		args:   []interface{}{true},
+		errmsg: "",
+	}, {
+		stmt:   Update(articles).SetStruct(article, other.C("id")),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: column not found in FROM.",
+	}, {
+		stmt:   Update(articles).SetStruct(article, articles.C("id")),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: sqlbuilder.structArticle has no fields for the columns of ARTICLE.",
+	}, {
+		stmt:   Update(articles.InnerJoin(other, articles.C("id").Eq(other.C("id")))).SetStruct(article),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: SetStruct can use only natural table.",
+	}}
+	for num, c := range cases {
+		mes, args, ok := c.Run()
+		if !ok {
+			t.Errorf(mes+" (case no.%d)", append(args, num)...)
+		}
+	}
+}
@@ -123,6 +123,20 @@ func (m *cTable) Columns() []Column {
 	return m.columns
 }
 
+// ColumnConfigs returns the configs of the columns of the table given, in
+// order, or nil when it is not a natural table
+func ColumnConfigs(tbl Table) []ColumnConfig {
+	t, ok := tbl.(*cTable)
+	if !ok {
+		return nil
+	}
+	configs := make([]ColumnConfig, 0, len(t.columns))
+	for _, col := range t.columns {
+		configs = append(configs, col.config())
+	}
+	return configs
+}
+
 func (m *cTable) Option() *TableOption {
 	return m.option
 }
@@ -155,6 +169,7 @@ func (m *cTable) ChangeColumn(trg Column, cc ColumnConfig) error {
 			if err != nil {
 				return err
 			}
+			m.renameOptionColumn(trg.column_name(), cc.Name())
 			return nil
 		}
 	}
@@ -172,6 +187,7 @@ func (m *cTable) ChangeColumnFirst(trg Column, cc ColumnConfig) error {
 			if err != nil {
 				return err
 			}
+			m.renameOptionColumn(trg.column_name(), cc.Name())
 			return nil
 		}
 	}
@@ -203,6 +219,7 @@ func (m *cTable) ChangeColumnAfter(trg Column, cc ColumnConfig, after Column) er
 				m.columns = backup
 				return err
 			}
+			m.renameOptionColumn(trg.column_name(), cc.Name())
 			return nil
 		}
 	}
@@ -230,7 +247,12 @@ func (m *cTable) addColumn(cc ColumnConfig, pos int) error {
 func (m *cTable) DropColumn(col Column) error {
 	for i := range m.columns {
 		if m.columns[i] == col {
-			return m.dropColumn(i)
+			err := m.dropColumn(i)
+			if err != nil {
+				return err
+			}
+			m.dropOptionColumn(col.column_name())
+			return nil
 		}
 	}
 	return newError("column not found.")
@@ -295,8 +317,227 @@ func (m *cTable) hasColumn(target Column) bool {
 	if acol, ok := target.(*cColumnAlias); ok {
 		return acol.hasColumn(m)
 	}
-	if sqlfn, ok := target.(*cSqlFunc); ok {
-		return sqlfn.hasColumn(m)
+	if expr, ok := target.(iExpression); ok {
+		return expr.hasColumn(m)
+	}
+	return false
+}
+
+// clone returns a copy of the table, with new columns of the same configs
+func (m *cTable) clone() *cTable {
+	option := *m.option
+	option.PrimaryKey = append([]string(nil), m.option.PrimaryKey...)
+	option.Unique = append([][]string(nil), m.option.Unique...)
+	option.Checks = append([]Check(nil), m.option.Checks...)
+	option.ForeignKeys = append([]ForeignKey(nil), m.option.ForeignKeys...)
+	t := &cTable{
+		name:    m.name,
+		option:  &option,
+		columns: make([]Column, 0, len(m.columns)),
+	}
+	for _, col := range m.columns {
+		t.columns = append(t.columns, col.config().toColumn(t))
+	}
+	return t
+}
+
+func (m *cTable) hasColumnName(name string) bool {
+	for _, column := range m.columns {
+		if column.column_name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// renameOptionColumn renames the column in the table option, after the
+// column was changed. The lists are copied as clone shares them.
+func (m *cTable) renameOptionColumn(old, new string) {
+	if old == new {
+		return
+	}
+	rename := func(list []string) []string {
+		if len(list) == 0 {
+			return list
+		}
+		renamed := make([]string, len(list))
+		for i, name := range list {
+			if name == old {
+				name = new
+			}
+			renamed[i] = name
+		}
+		return renamed
+	}
+
+	m.option.PrimaryKey = rename(m.option.PrimaryKey)
+	uniques := make([][]string, 0, len(m.option.Unique))
+	for _, unique := range m.option.Unique {
+		uniques = append(uniques, rename(unique))
+	}
+	m.option.Unique = uniques
+	checks := make([]Check, 0, len(m.option.Checks))
+	for _, check := range m.option.Checks {
+		if check.uses(old) {
+			check.Condition = renameCondition(check.Condition, m, old, new)
+		}
+		checks = append(checks, check)
+	}
+	m.option.Checks = checks
+	fkeys := make([]ForeignKey, 0, len(m.option.ForeignKeys))
+	for _, fk := range m.option.ForeignKeys {
+		fk.Columns = rename(fk.Columns)
+		fkeys = append(fkeys, fk)
+	}
+	m.option.ForeignKeys = fkeys
+}
+
+// dropOptionColumn removes the column from the primary key and the unique
+// keys, and removes the checks and foreign keys using it, after the column
+// was dropped. The lists are copied as clone shares them.
+func (m *cTable) dropOptionColumn(name string) {
+	drop := func(list []string) []string {
+		var kept []string
+		for _, n := range list {
+			if n != name {
+				kept = append(kept, n)
+			}
+		}
+		return kept
+	}
+
+	m.option.PrimaryKey = drop(m.option.PrimaryKey)
+	var uniques [][]string
+	for _, unique := range m.option.Unique {
+		if unique = drop(unique); len(unique) != 0 {
+			uniques = append(uniques, unique)
+		}
+	}
+	m.option.Unique = uniques
+	var checks []Check
+	for _, check := range m.option.Checks {
+		if !check.uses(name) {
+			checks = append(checks, check)
+		}
+	}
+	m.option.Checks = checks
+	var fkeys []ForeignKey
+	for _, fk := range m.option.ForeignKeys {
+		if len(drop(fk.Columns)) == len(fk.Columns) {
+			fkeys = append(fkeys, fk)
+		}
+	}
+	m.option.ForeignKeys = fkeys
+}
+
+// checkOption returns an error if the table option uses columns not found in
+// the table or the primary key is declared more than once
+func (m *cTable) checkOption() error {
+	var primary int
+	for _, col := range m.columns {
+		if col.config().Option().PrimaryKey {
+			primary++
+		}
+	}
+	if len(m.option.PrimaryKey) != 0 && primary != 0 {
+		return newError("primary key is declared by both ColumnOption and TableOption.")
+	} else if primary > 1 {
+		return newError("%d columns are PrimaryKey, use TableOption.PrimaryKey for composite primary keys.", primary)
+	}
+
+	names := append([]string(nil), m.option.PrimaryKey...)
+	for _, unique := range m.option.Unique {
+		names = append(names, unique...)
+	}
+	for _, name := range names {
+		if !m.hasColumnName(name) {
+			return newError("column %s.%s was not found.", m.name, name)
+		}
+	}
+	for _, check := range m.option.Checks {
+		if err := check.check(m); err != nil {
+			return err
+		}
+	}
+	for _, fk := range m.option.ForeignKeys {
+		if err := fk.check(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *cTable) AddForeignKey(fk ForeignKey) error {
+	if err := fk.check(m); err != nil {
+		return err
+	}
+	m.option.ForeignKeys = append(m.option.ForeignKeys, fk)
+	return nil
+}
+
+func (m *cTable) DropForeignKey(name string) error {
+	for i := range m.option.ForeignKeys {
+		if m.option.ForeignKeys[i].Name == name {
+			m.option.ForeignKeys = append(m.option.ForeignKeys[:i], m.option.ForeignKeys[i+1:]...)
+			return nil
+		}
+	}
+	return newError("foreign key %s.%s was not found.", m.name, name)
+}
+
+// isPrimaryKey reports whether the column is part of the primary key
+func (m *cTable) isPrimaryKey(col Column) bool {
+	if cc := col.config(); cc != nil && cc.Option().PrimaryKey {
+		return true
+	}
+	for _, name := range m.option.PrimaryKey {
+		if name == col.column_name() {
+			return true
+		}
+	}
+	return false
+}
+
+// isUniqueKey reports whether the columns given are exactly the columns of a
+// unique key or primary key of the table
+func (m *cTable) isUniqueKey(columns []Column) bool {
+	if len(columns) == 0 {
+		return false
+	}
+	names := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		names[col.column_name()] = true
+	}
+	sameNames := func(list []string) bool {
+		if len(list) != len(names) {
+			return false
+		}
+		for _, name := range list {
+			if !names[name] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var primary []string
+	for _, col := range m.columns {
+		if opt := col.config().Option(); opt.PrimaryKey {
+			primary = append(primary, col.column_name())
+		} else if opt.Unique && sameNames([]string{col.column_name()}) {
+			return true
+		}
+	}
+	if len(primary) > 0 && sameNames(primary) {
+		return true
+	}
+	if len(m.option.PrimaryKey) > 0 && sameNames(m.option.PrimaryKey) {
+		return true
+	}
+	for _, unique := range m.option.Unique {
+		if sameNames(unique) {
+			return true
+		}
 	}
 	return false
 }
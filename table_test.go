@@ -248,3 +248,24 @@ func TestTableColumnOperation(t *testing.T) {
 	})
 
 }
+
+func TestColumnConfigs(t *testing.T) {
+	table1 := NewTable(
+		"TABLE_NAME",
+		nil,
+		IntColumn("id", &ColumnOption{PrimaryKey: true}),
+		StringColumn("name", nil),
+	)
+
+	Convey("ColumnConfigs", t, func() {
+		configs := ColumnConfigs(table1)
+		So(len(configs), ShouldEqual, 2)
+		So(configs[0].Name(), ShouldEqual, "id")
+		So(configs[0].Option().PrimaryKey, ShouldBeTrue)
+		So(configs[1].Name(), ShouldEqual, "name")
+		So(configs[1].Type(), ShouldEqual, ColumnTypeString)
+
+		joined := table1.InnerJoin(table1, table1.C("id").Eq(table1.C("id")))
+		So(ColumnConfigs(joined), ShouldBeNil)
+	})
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// SavepointAction is the type of a savepoint statement
+type SavepointAction int
+
+const (
+	// SavepointCreate starts a new savepoint
+	SavepointCreate SavepointAction = iota
+	// SavepointRelease keeps the changes made since the savepoint started
+	SavepointRelease
+	// SavepointRollback discards the changes made since the savepoint
+	// started
+	SavepointRollback
+)
+
+func (a SavepointAction) String() string {
+	switch a {
+	case SavepointCreate:
+		return "SAVEPOINT"
+	case SavepointRelease:
+		return "RELEASE SAVEPOINT"
+	case SavepointRollback:
+		return "ROLLBACK TO SAVEPOINT"
+	}
+	return fmt.Sprintf("SavepointAction(%d)", int(a))
+}
+
+// TxOptions are the options of a transaction started by Transaction
+type TxOptions struct {
+	// Isolation is the isolation level of the transaction, the database
+	// default is used when sql.LevelDefault
+	Isolation sql.IsolationLevel
+	// ReadOnly rejects changes made within the transaction
+	ReadOnly bool
+}
+
+// Transaction calls fn with an Executor running statements within a new
+// transaction, which is committed when fn returns nil and rolled back when fn
+// returns an error or panics. The statements beginning the transaction are
+// generated by the Dialect of the Executor, from the options given.
+//
+// The Querier of the Executor given must be a *sql.DB or *sql.Conn. When it
+// is the Executor given to fn (or a *sql.Tx), the changes made by fn are
+// nested within a savepoint instead, which is released or rolled back in the
+// same way and does not accept options.
+func Transaction(ctx context.Context, e Executor, opts *TxOptions, fn func(tx Executor) error) (err error) {
+	if tx, ok := e.(*txExecutor); ok {
+		return tx.savepoint(ctx, opts, fn)
+	}
+	switch db := e.Querier().(type) {
+	case *sql.Tx:
+		tx := &txExecutor{executor: executor{Buildable: e, db: db}}
+		return tx.savepoint(ctx, opts, fn)
+	case *sql.Conn:
+		tx := &txExecutor{executor: executor{Buildable: e, db: db}}
+		return tx.begin(ctx, opts, fn)
+	case *sql.DB:
+		var conn *sql.Conn
+		if conn, err = db.Conn(ctx); err != nil {
+			return err
+		}
+		defer func() {
+			if e := conn.Close(); e != nil && err == nil {
+				err = e
+			}
+		}()
+		tx := &txExecutor{executor: executor{Buildable: e, db: conn}, conn: conn}
+		return tx.begin(ctx, opts, fn)
+	}
+	return newError("%T can not begin transactions.", e.Querier())
+}
+
+// txExecutor is the Executor given to the function run by Transaction
+type txExecutor struct {
+	executor
+	// depth is the number of savepoints started
+	depth int
+	// conn is the connection taken from a *sql.DB, if any
+	conn *sql.Conn
+}
+
+func (t *txExecutor) begin(ctx context.Context, opts *TxOptions, fn func(tx Executor) error) error {
+	if opts == nil {
+		opts = &TxOptions{}
+	}
+	begin, err := t.Dialect().BeginToString(opts.Isolation, opts.ReadOnly)
+	if err != nil {
+		return err
+	}
+	return t.run(ctx, begin, []string{"COMMIT"}, []string{"ROLLBACK"}, fn)
+}
+
+func (t *txExecutor) savepoint(ctx context.Context, opts *TxOptions, fn func(tx Executor) error) error {
+	if opts != nil && *opts != (TxOptions{}) {
+		return newError("transaction options can not be used with savepoints.")
+	}
+	d := t.Dialect()
+	name := fmt.Sprintf("sp_%d", t.depth+1)
+	var statements [3]string
+	for idx, action := range []SavepointAction{SavepointCreate, SavepointRelease, SavepointRollback} {
+		query, err := d.SavepointToString(action, name)
+		if err != nil {
+			return err
+		}
+		statements[idx] = query
+	}
+	nested := &txExecutor{executor: t.executor, depth: t.depth + 1, conn: t.conn}
+	// a savepoint rolled back remains started until released
+	return nested.run(ctx, statements[:1], statements[1:2], []string{statements[2], statements[1]}, fn)
+}
+
+// run executes the begin statements, calls fn and then executes either the
+// commit or the rollback statements
+func (t *txExecutor) run(ctx context.Context, begin, commit, rollback []string, fn func(tx Executor) error) (err error) {
+	if err = t.execAll(ctx, begin); err != nil {
+		return err
+	}
+	done := false
+	defer func() {
+		if done {
+			return
+		}
+		p := recover()
+		// roll back even when the context is done
+		if e := t.execAll(context.WithoutCancel(ctx), rollback); e != nil {
+			t.discard()
+		}
+		if p != nil {
+			panic(p)
+		}
+	}()
+	if err = fn(t); err != nil {
+		return err
+	}
+	if err = t.execAll(ctx, commit); err != nil {
+		return err
+	}
+	done = true
+	return nil
+}
+
+func (t *txExecutor) execAll(ctx context.Context, queries []string) error {
+	for _, query := range queries {
+		if _, err := t.db.ExecContext(ctx, query); err != nil {
+			return &QueryError{Query: query, Err: err}
+		}
+	}
+	return nil
+}
+
+// discard prevents a connection taken from a *sql.DB being reused when its
+// transaction could not be rolled back
+func (t *txExecutor) discard() {
+	if t.conn != nil {
+		_ = t.conn.Raw(func(interface{}) error {
+			return driver.ErrBadConn
+		})
+	}
+}
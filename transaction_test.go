@@ -0,0 +1,155 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTransaction(t *testing.T) {
+	tbl := NewTable(
+		"TABLE_A",
+		&TableOption{},
+		IntColumn("id", &ColumnOption{
+			PrimaryKey: true,
+		}),
+	)
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	ex := NewExecutor(db, NewBuildable(TestingDialect{}))
+	if _, err = ex.Exec(ex.CreateTable(tbl)); err != nil {
+		t.Fatal(err)
+	}
+	ids := func() (ids []int64) {
+		rows, err := ex.Query(ex.Select(tbl).Columns(tbl.C("id")).OrderBy(false, tbl.C("id")))
+		So(err, ShouldBeNil)
+		for rows.Next() {
+			var id int64
+			So(rows.Scan(&id), ShouldBeNil)
+			ids = append(ids, id)
+		}
+		So(rows.Close(), ShouldBeNil)
+		return
+	}
+	insert := func(tx Executor, id int64) error {
+		_, err := tx.Exec(tx.Insert(tbl).Values(id))
+		return err
+	}
+	failure := errors.New("failure")
+
+	Convey("Commit", t, func() {
+		err := Transaction(ctx, ex, nil, func(tx Executor) error {
+			So(tx.Querier(), ShouldHaveSameTypeAs, &sql.Conn{})
+			return insert(tx, 1)
+		})
+		So(err, ShouldBeNil)
+		So(ids(), ShouldEqual, []int64{1})
+	})
+
+	Convey("Rollback", t, func() {
+		err := Transaction(ctx, ex, nil, func(tx Executor) error {
+			So(insert(tx, 2), ShouldBeNil)
+			return failure
+		})
+		So(err, ShouldEqual, failure)
+		So(ids(), ShouldEqual, []int64{1})
+
+		So(func() {
+			_ = Transaction(ctx, ex, nil, func(tx Executor) error {
+				So(insert(tx, 2), ShouldBeNil)
+				panic(failure)
+			})
+		}, ShouldPanicWith, failure)
+		So(ids(), ShouldEqual, []int64{1})
+	})
+
+	Convey("Savepoints", t, func() {
+		err := Transaction(ctx, ex, nil, func(tx Executor) error {
+			So(insert(tx, 2), ShouldBeNil)
+			So(Transaction(ctx, tx, nil, func(tx Executor) error {
+				return insert(tx, 3)
+			}), ShouldBeNil)
+			So(Transaction(ctx, tx, nil, func(tx Executor) error {
+				So(insert(tx, 4), ShouldBeNil)
+				So(Transaction(ctx, tx, nil, func(tx Executor) error {
+					return insert(tx, 5)
+				}), ShouldBeNil)
+				return failure
+			}), ShouldEqual, failure)
+			So(Transaction(ctx, tx, &TxOptions{ReadOnly: true}, func(tx Executor) error {
+				return nil
+			}), ShouldNotBeNil)
+			return nil
+		})
+		So(err, ShouldBeNil)
+		So(ids(), ShouldEqual, []int64{1, 2, 3})
+
+		sqlTx, err := db.Begin()
+		So(err, ShouldBeNil)
+		err = Transaction(ctx, NewExecutor(sqlTx, ex), nil, func(tx Executor) error {
+			So(insert(tx, 6), ShouldBeNil)
+			return failure
+		})
+		So(err, ShouldEqual, failure)
+		So(insert(NewExecutor(sqlTx, ex), 7), ShouldBeNil)
+		So(sqlTx.Commit(), ShouldBeNil)
+		So(ids(), ShouldEqual, []int64{1, 2, 3, 7})
+	})
+
+	Convey("Options", t, func() {
+		err := Transaction(ctx, ex, &TxOptions{Isolation: sql.LevelSerializable}, func(tx Executor) error {
+			return nil
+		})
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "BEGIN ISOLATION LEVEL SERIALIZABLE")
+
+		conn, err := db.Conn(ctx)
+		So(err, ShouldBeNil)
+		err = Transaction(ctx, NewExecutor(conn, ex), nil, func(tx Executor) error {
+			So(tx.Querier(), ShouldEqual, conn)
+			return insert(tx, 8)
+		})
+		So(err, ShouldBeNil)
+		So(conn.Close(), ShouldBeNil)
+		So(ids(), ShouldEqual, []int64{1, 2, 3, 7, 8})
+	})
+
+	Convey("Querier", t, func() {
+		err := Transaction(ctx, NewExecutor(nil, ex), nil, func(tx Executor) error {
+			return nil
+		})
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldEqual, "sqlbuilder: <nil> can not begin transactions.")
+	})
+}
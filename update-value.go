@@ -23,21 +23,63 @@ package sqlbuilder
 
 type cUpdateValue struct {
 	col Column
-	val literal
+	val serializable
 }
 
 func newUpdateValue(col Column, val interface{}) cUpdateValue {
+	if vc, ok := val.(Column); ok {
+		return cUpdateValue{
+			col: col,
+			val: vc,
+		}
+	}
 	return cUpdateValue{
 		col: col,
 		val: toLiteral(val),
 	}
 }
 
+// checkUpdateValue returns an error if the value given is a column, or an
+// expression of columns, not found in the table. Excluded columns are only
+// accepted by upserts, when excluded is true.
+func checkUpdateValue(t Table, val interface{}, excluded bool) error {
+	col, ok := val.(Column)
+	if !ok {
+		return nil
+	}
+	if err := GetColumnError(col); err != nil {
+		return err
+	}
+	switch c := col.(type) {
+	case *cExcludedColumn:
+		if !excluded {
+			return newError("excluded column can only be used by DoUpdateSet.")
+		}
+		if !t.hasColumn(c.column) {
+			return newError("excluded column not found in table.")
+		}
+		return nil
+	case *cColumnAlias:
+		return checkUpdateValue(t, c.column, excluded)
+	case iExpression:
+		for _, operand := range c.columns() {
+			if err := checkUpdateValue(t, operand, excluded); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if col == Star || !t.hasColumn(col) {
+		return newError("column %s.%s was not found.", col.table_name(), col.column_name())
+	}
+	return nil
+}
+
 func (c cUpdateValue) serialize(b *builder) {
-	if !c.col.acceptType(c.val) {
+	if lit, ok := c.val.(literal); ok && !c.col.acceptType(lit) {
 		b.SetError(newError("%s column not accept %T.",
 			c.col.config().Type().String(),
-			c.val.Raw(),
+			lit.Raw(),
 		))
 		return
 	}
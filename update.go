@@ -24,10 +24,18 @@ package sqlbuilder
 // UpdateBuilder is the Buildable interface wrapping of Update
 type UpdateBuilder interface {
 	Set(col Column, val interface{}) UpdateBuilder
+	// SetStruct sets the columns of the table to the fields of the struct
+	// given (see NewTableFromStruct), limited to the columns given if any
+	SetStruct(structure interface{}, only ...Column) UpdateBuilder
 	Where(cond Condition) UpdateBuilder
 	Limit(limit int) UpdateBuilder
 	Offset(offset int) UpdateBuilder
 	OrderBy(desc bool, columns ...Column) UpdateBuilder
+
+	// Returning sets the columns of the updated rows to return, all columns
+	// are returned when none are given
+	Returning(columns ...Column) UpdateBuilder
+
 	ToSql() (query string, args []interface{}, err error)
 
 	privateUpdate()
@@ -42,6 +50,8 @@ type cUpdate struct {
 	limit   int
 	offset  int
 
+	returning cSelectColumnList
+
 	err error
 
 	dialect Dialect
@@ -81,10 +91,57 @@ func (c *cUpdate) Set(col Column, val interface{}) UpdateBuilder {
 		c.err = newError("column not found in FROM.")
 		return c
 	}
+	if err := checkUpdateValue(c.table, val, false); err != nil {
+		c.err = err
+		return c
+	}
 	c.set = append(c.set, newUpdateValue(col, val))
 	return c
 }
 
+// SetStruct calls Set for each column of the table matching a field of the
+// struct given, by the column names of NewTableFromStruct. Primary key columns
+// and zero omitempty fields are skipped. When columns are given, the columns
+// set are limited to the columns given.
+func (c *cUpdate) SetStruct(structure interface{}, only ...Column) UpdateBuilder {
+	if c.err != nil {
+		return c
+	}
+	tbl, ok := c.table.(*cTable)
+	if !ok {
+		c.err = newError("SetStruct can use only natural table.")
+		return c
+	}
+	for _, col := range only {
+		if !tbl.hasColumn(col) {
+			c.err = newError("column not found in FROM.")
+			return c
+		}
+	}
+	columns, values, err := structFieldValues(tbl, structure, func(col Column) bool {
+		if tbl.isPrimaryKey(col) {
+			return true
+		}
+		if len(only) == 0 {
+			return false
+		}
+		for _, o := range only {
+			if SameColumn(o, col) {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		c.err = err
+		return c
+	}
+	for i := range columns {
+		c.Set(columns[i], values[i])
+	}
+	return c
+}
+
 // Where sets WHERE clause.  The cond is filter condition.
 func (c *cUpdate) Where(cond Condition) UpdateBuilder {
 	if c.err != nil {
@@ -127,6 +184,16 @@ func (c *cUpdate) OrderBy(desc bool, columns ...Column) UpdateBuilder {
 	return c
 }
 
+// Returning sets RETURNING clause. Returns all columns (use *) if none are
+// given.
+func (c *cUpdate) Returning(columns ...Column) UpdateBuilder {
+	if c.err != nil {
+		return c
+	}
+	c.returning, c.err = checkReturning(c.table, columns)
+	return c
+}
+
 // ToSql generates query string, placeholder arguments, and returns err on errors
 func (c *cUpdate) ToSql() (query string, args []interface{}, err error) {
 	b := newBuilder(c.dialect)
@@ -155,6 +222,9 @@ func (c *cUpdate) ToSql() (query string, args []interface{}, err error) {
 		b.AppendItem(c.where)
 	}
 
+	// RETURNING
+	serializeReturning(b, c.returning)
+
 	// ORDER BY
 	if c.orderBy != nil {
 		b.Append(" ORDER BY ")
@@ -61,6 +61,20 @@ func TestUpdate(t *testing.T) {
 		query:  `UPDATE "TABLE_A" SET "test1"=?, "test2"=? WHERE "TABLE_A"."id"=?;`,
 		args:   []interface{}{int64(10), int64(20), int64(1)},
 		errmsg: "",
+	}, {
+		stmt: Update(table1).Where(table1.C("id").Eq(1)).
+			Set(table1.C("test1"), 10).
+			Returning(table1.C("id"), table1.C("test1").As("t1")),
+		query:  `UPDATE "TABLE_A" SET "test1"=? WHERE "TABLE_A"."id"=? RETURNING "TABLE_A"."id", "TABLE_A"."test1" AS "t1";`,
+		args:   []interface{}{int64(10), int64(1)},
+		errmsg: "",
+	}, {
+		stmt: Update(table1).
+			Set(table1.C("test1"), 10).
+			Returning(table2.C("id")),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: column not found in table: \"id\"",
 	}, {
 		stmt: Update(nil).Where(table1.C("id").Eq(1)).
 			Set(table1.C("test1"), 10).
@@ -79,6 +93,30 @@ func TestUpdate(t *testing.T) {
 		query:  ``,
 		args:   []interface{}{},
 		errmsg: "sqlbuilder: int column not accept string.",
+	}, {
+		stmt: Update(table1).Where(table1.C("id").Eq(1)).
+			Set(table1.C("test1"), table1.C("test2").Add(1)),
+		query:  `UPDATE "TABLE_A" SET "test1"="TABLE_A"."test2"+? WHERE "TABLE_A"."id"=?;`,
+		args:   []interface{}{int64(1), int64(1)},
+		errmsg: "",
+	}, {
+		stmt: Update(table1).Where(table1.C("id").Eq(1)).
+			Set(table1.C("test1"), table2.C("id")),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: column TABLE_B.id was not found.",
+	}, {
+		stmt: Update(table1).Where(table1.C("id").Eq(1)).
+			Set(table1.C("test1"), Coalesce(table1.C("test2"), table2.C("id").Mul(2))),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: column TABLE_B.id was not found.",
+	}, {
+		stmt: Update(table1).Where(table1.C("id").Eq(1)).
+			Set(table1.C("test1"), Excluded(table1.C("test2"))),
+		query:  ``,
+		args:   []interface{}{},
+		errmsg: "sqlbuilder: excluded column can only be used by DoUpdateSet.",
 	}, {
 		stmt:   Update(tableJoined),
 		query:  ``,
@@ -0,0 +1,241 @@
+// Copyright (c) 2014 umisama <Takaaki IBARAKI>
+// Copyright (c)  The Go-CoreLibs Authors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sqlbuilder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FrameBound is the start or the end of the frame of a window, see
+// WindowSpec.Rows and WindowSpec.Range
+type FrameBound struct {
+	kind   frameBoundKind
+	offset int
+}
+
+type frameBoundKind int
+
+const (
+	frameBoundNone frameBoundKind = iota
+	frameBoundUnboundedPreceding
+	frameBoundPreceding
+	frameBoundCurrentRow
+	frameBoundFollowing
+	frameBoundUnboundedFollowing
+)
+
+var (
+	// UnboundedPreceding represents the "UNBOUNDED PRECEDING" frame bound
+	UnboundedPreceding = FrameBound{kind: frameBoundUnboundedPreceding}
+	// CurrentRow represents the "CURRENT ROW" frame bound
+	CurrentRow = FrameBound{kind: frameBoundCurrentRow}
+	// UnboundedFollowing represents the "UNBOUNDED FOLLOWING" frame bound
+	UnboundedFollowing = FrameBound{kind: frameBoundUnboundedFollowing}
+)
+
+// Preceding returns the "offset PRECEDING" frame bound
+func Preceding(offset int) FrameBound {
+	return FrameBound{kind: frameBoundPreceding, offset: offset}
+}
+
+// Following returns the "offset FOLLOWING" frame bound
+func Following(offset int) FrameBound {
+	return FrameBound{kind: frameBoundFollowing, offset: offset}
+}
+
+func (f FrameBound) String() string {
+	switch f.kind {
+	case frameBoundUnboundedPreceding:
+		return "UNBOUNDED PRECEDING"
+	case frameBoundPreceding:
+		return strconv.Itoa(f.offset) + " PRECEDING"
+	case frameBoundCurrentRow:
+		return "CURRENT ROW"
+	case frameBoundFollowing:
+		return strconv.Itoa(f.offset) + " FOLLOWING"
+	case frameBoundUnboundedFollowing:
+		return "UNBOUNDED FOLLOWING"
+	}
+	return ""
+}
+
+// WindowSpec is the definition of a window, used by the OVER clause of a
+// window function (see SqlFunc.Over) or by the WINDOW clause of a SELECT
+// statement (see SelectBuilder.Window)
+type WindowSpec interface {
+	serializable
+
+	// PartitionBy sets "PARTITION BY" clause by the columns
+	PartitionBy(columns ...Column) WindowSpec
+	// OrderBy adds to "ORDER BY" clause. Use descending order if the desc is
+	// true, by the columns
+	OrderBy(desc bool, columns ...Column) WindowSpec
+	// Rows sets the "ROWS BETWEEN start AND end" frame
+	Rows(start, end FrameBound) WindowSpec
+	// Range sets the "RANGE BETWEEN start AND end" frame
+	Range(start, end FrameBound) WindowSpec
+
+	columns() []Column
+	privateWindow()
+}
+
+type cWindow struct {
+	partitionBy []Column
+	orderBy     []*cSelectOrderBy
+	frame       string
+	start       FrameBound
+	end         FrameBound
+
+	err error
+}
+
+// Window returns a new window specification, to be completed by the
+// methods of WindowSpec. An empty window covers all the rows of the result.
+func Window() WindowSpec {
+	return &cWindow{}
+}
+
+func (w *cWindow) privateWindow() {
+	// nop
+}
+
+func (w *cWindow) PartitionBy(columns ...Column) WindowSpec {
+	if w.err != nil {
+		return w
+	}
+	w.partitionBy = columns
+	return w
+}
+
+func (w *cWindow) OrderBy(desc bool, columns ...Column) WindowSpec {
+	if w.err != nil {
+		return w
+	}
+	for _, c := range columns {
+		w.orderBy = append(w.orderBy, newOrderBy(desc, c))
+	}
+	return w
+}
+
+func (w *cWindow) Rows(start, end FrameBound) WindowSpec {
+	return w.setFrame("ROWS", start, end)
+}
+
+func (w *cWindow) Range(start, end FrameBound) WindowSpec {
+	return w.setFrame("RANGE", start, end)
+}
+
+func (w *cWindow) setFrame(frame string, start, end FrameBound) WindowSpec {
+	if w.err != nil {
+		return w
+	}
+	switch {
+	case start.kind == frameBoundNone || end.kind == frameBoundNone:
+		w.err = newError("frame bound is not set.")
+	case start.kind == frameBoundUnboundedFollowing:
+		w.err = newError("frame can not start with UNBOUNDED FOLLOWING.")
+	case end.kind == frameBoundUnboundedPreceding:
+		w.err = newError("frame can not end with UNBOUNDED PRECEDING.")
+	case start.kind > end.kind:
+		w.err = newError("frame starts with %s, after its end %s.", start, end)
+	case start.offset < 0 || end.offset < 0:
+		w.err = newError("frame offset can not be negative.")
+	case start.kind == end.kind && start.kind == frameBoundPreceding && start.offset < end.offset,
+		start.kind == end.kind && start.kind == frameBoundFollowing && start.offset > end.offset:
+		w.err = newError("frame starts with %s, after its end %s.", start, end)
+	default:
+		w.frame, w.start, w.end = frame, start, end
+	}
+	return w
+}
+
+func (w *cWindow) columns() []Column {
+	columns := append([]Column(nil), w.partitionBy...)
+	for _, o := range w.orderBy {
+		columns = append(columns, o.column)
+	}
+	return columns
+}
+
+func (w *cWindow) serialize(b *builder) {
+	if w.err != nil {
+		b.SetError(w.err)
+		return
+	}
+	var space bool
+	b.Append("(")
+	if len(w.partitionBy) > 0 {
+		b.Append("PARTITION BY ")
+		b.AppendItem(cSqlFuncColumnList(w.partitionBy))
+		space = true
+	}
+	if len(w.orderBy) > 0 {
+		if space {
+			b.Append(" ")
+		}
+		b.Append("ORDER BY ")
+		for idx, o := range w.orderBy {
+			if idx > 0 {
+				b.Append(", ")
+			}
+			b.AppendItem(o)
+		}
+		space = true
+	}
+	if w.frame != "" {
+		if space {
+			b.Append(" ")
+		}
+		b.Append(w.frame + " BETWEEN " + w.start.String() + " AND " + w.end.String())
+	}
+	b.Append(")")
+}
+
+func (w *cWindow) Describe() (output string) {
+	var parts []string
+	if len(w.partitionBy) > 0 {
+		parts = append(parts, "PARTITION BY "+cSqlFuncColumnList(w.partitionBy).Describe())
+	}
+	for _, o := range w.orderBy {
+		parts = append(parts, o.Describe())
+	}
+	if w.frame != "" {
+		parts = append(parts, w.frame+" BETWEEN "+w.start.String()+" AND "+w.end.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// cNamedWindow is a window defined by the WINDOW clause of a SELECT statement
+type cNamedWindow struct {
+	name   string
+	window WindowSpec
+}
+
+func (w *cNamedWindow) serialize(b *builder) {
+	b.Append(b.dialect.QuoteField(w.name) + " AS ")
+	b.AppendItem(w.window)
+}
+
+func (w *cNamedWindow) Describe() (output string) {
+	return strconv.Quote(w.name) + " AS " + w.window.Describe()
+}